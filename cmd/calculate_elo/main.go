@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
@@ -304,7 +305,7 @@ func main() {
 		log.Fatalf("Ошибка загрузки конфигурации: %v", err)
 	}
 
-	db.InitDB()
+	db.InitDB(context.Background())
 	defer db.CloseDB()
 
 	for {
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"football-data-miner/internal/db"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+const usage = `Матчи, отложенные после исчерпания попыток загрузки (состояние parked).
+
+Команды:
+  list    [-league ID]            показать отложенные матчи с причиной последней ошибки
+  retry   -match ID | -league ID  вернуть матчи в очередь со сброшенным счетчиком попыток
+  abandon -match ID | -league ID  отказаться от загрузки: сезон перестанет их ждать
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Print(usage)
+		os.Exit(2)
+	}
+	command, args := os.Args[1], os.Args[2:]
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	matchID := flags.Int("match", 0, "ID матча")
+	leagueID := flags.Int("league", 0, "ID лиги")
+	flags.Parse(args)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db.InitDB(ctx)
+	defer db.CloseDB()
+
+	var err error
+	switch command {
+	case "list":
+		err = list(ctx, *leagueID)
+	case "retry":
+		err = update(ctx, *matchID, *leagueID, db.RetryParkedMatches, "возвращено в очередь")
+	case "abandon":
+		err = update(ctx, *matchID, *leagueID, db.AbandonParkedMatches, "снято с загрузки")
+	default:
+		fmt.Print(usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Printf("Ошибка: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func list(ctx context.Context, leagueID int) error {
+	parked, err := db.ListParkedMatches(ctx, leagueID)
+	if err != nil {
+		return err
+	}
+	for _, p := range parked {
+		fmt.Printf("Матч ID=%d (лига %d, сезон %s): попыток %d, отложен %s\n    %s\n",
+			p.MatchID, p.LeagueID, p.Season, p.Attempts, p.UpdatedAt.Format("2006-01-02 15:04"), p.LastError)
+	}
+	fmt.Printf("Отложено матчей: %d\n", len(parked))
+	return nil
+}
+
+func update(ctx context.Context, matchID, leagueID int, apply func(context.Context, int, int) (int, error), done string) error {
+	if matchID == 0 && leagueID == 0 {
+		return fmt.Errorf("нужен -match или -league")
+	}
+	n, err := apply(ctx, matchID, leagueID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Матчей %s: %d\n", done, n)
+	return nil
+}
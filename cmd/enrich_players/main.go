@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"football-data-miner/internal/api"
+	"football-data-miner/internal/db"
+	"football-data-miner/internal/models"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Заполняет профили игроков (дата рождения, гражданство, рост, вес, фото) из /players
+// для обработанных сезонов. Сезон запрашивается, только если в нем есть игроки,
+// не проверенные за последние -stale-days: после полного прохода по страницам
+// проверенными считаются все игроки сезона, даже те, кого /players не вернул.
+func main() {
+	leagueID := flag.Int("league", 0, "ID лиги (0 — все обработанные сезоны)")
+	season := flag.String("season", "", "сезон (пусто — все)")
+	staleDays := flag.Int("stale-days", 30, "через сколько дней профиль считается устаревшим")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db.InitDB(ctx)
+	defer db.CloseDB()
+
+	provider, err := api.NewProviderFromEnv()
+	if err != nil {
+		fmt.Printf("Ошибка инициализации провайдера данных: %v\n", err)
+		return
+	}
+	api.EnableArchive(db.PayloadArchive{})
+
+	seasons, err := db.GetProcessedSeasons(ctx)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		return
+	}
+
+	staleBefore := time.Now().AddDate(0, 0, -*staleDays)
+	for _, s := range seasons {
+		if ctx.Err() != nil {
+			break
+		}
+		if (*leagueID != 0 && s.LeagueID != *leagueID) || (*season != "" && s.Season != *season) {
+			continue
+		}
+
+		stale, err := db.CountStalePlayers(ctx, s.LeagueID, s.Season, staleBefore)
+		if err != nil {
+			fmt.Printf("%v\n", err)
+			continue
+		}
+		if stale == 0 {
+			continue
+		}
+
+		fmt.Printf("Лига %d, сезон %s: непроверенных профилей %d. Обновляем...\n", s.LeagueID, s.Season, stale)
+		saved, err := enrichSeason(ctx, provider, s)
+		if err != nil {
+			fmt.Printf("Ошибка обновления профилей лиги %d, сезон %s: %v\n", s.LeagueID, s.Season, err)
+		}
+		fmt.Printf("Лига %d, сезон %s: сохранено профилей %d\n", s.LeagueID, s.Season, saved)
+	}
+}
+
+func enrichSeason(ctx context.Context, provider api.FootballProvider, s models.Season) (int, error) {
+	saved := 0
+	for page, total := 1, 1; page <= total; page++ {
+		if err := ctx.Err(); err != nil {
+			return saved, err
+		}
+		players, pages, err := provider.FetchPlayerProfiles(ctx, s.LeagueID, s.Season, page)
+		if err != nil {
+			return saved, err
+		}
+		total = pages
+
+		for _, player := range players {
+			if err := db.SavePlayerProfile(ctx, player); err != nil {
+				fmt.Printf("%v\n", err)
+				continue
+			}
+			saved++
+		}
+	}
+	return saved, db.MarkSeasonPlayersChecked(ctx, s.LeagueID, s.Season)
+}
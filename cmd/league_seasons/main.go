@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"football-data-miner/internal/api"
+	"football-data-miner/internal/db"
+	"football-data-miner/internal/models"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+const usage = `Управление очередью сезонов league_seasons.
+
+Команды:
+  list   [-league ID]                                  показать сезоны в порядке обработки
+  add    -league ID -season YYYY [-priority N]         добавить сезон или изменить приоритет
+                                                       (без -priority приоритет сезона в очереди не меняется)
+  remove -league ID -season YYYY                       удалить сезон из очереди
+  reset  -league ID -season YYYY                       снова открыть сезон для обработки
+  seed   -league ID [-from YYYY] [-priority N] [-require statistics,lineups]
+                                                       добавить сезоны лиги из /leagues
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Print(usage)
+		os.Exit(2)
+	}
+	command, args := os.Args[1], os.Args[2:]
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	leagueID := flags.Int("league", 0, "ID лиги")
+	season := flags.String("season", "", "сезон (год начала, например 2024)")
+	priority := flags.Int("priority", 0, "приоритет: сезоны с большим значением обрабатываются раньше")
+	from := flags.String("from", "", "seed: не добавлять сезоны раньше этого")
+	require := flags.String("require", "statistics,lineups", "seed: обязательное покрытие через запятую ("+strings.Join(coverageNames, ", ")+")")
+	flags.Parse(args)
+	// Без явного -priority приоритет уже стоящих в очереди сезонов не трогаем
+	prioritySet := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "priority" {
+			prioritySet = true
+		}
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db.InitDB(ctx)
+	defer db.CloseDB()
+
+	var err error
+	switch command {
+	case "list":
+		err = list(ctx, *leagueID)
+	case "add":
+		if err = requireSeason(*leagueID, *season); err == nil {
+			err = db.SaveLeagueSeason(ctx, models.LeagueSeason{LeagueID: *leagueID, Season: *season, Priority: *priority}, prioritySet)
+		}
+		if err == nil {
+			fmt.Printf("Сезон лиги %d, сезон %s в очереди.\n", *leagueID, *season)
+		}
+	case "remove":
+		err = updateSeason(ctx, *leagueID, *season, db.RemoveLeagueSeason, "удален из очереди")
+	case "reset":
+		err = updateSeason(ctx, *leagueID, *season, db.ResetLeagueSeason, "снова открыт для обработки")
+	case "seed":
+		err = seed(ctx, *leagueID, *from, *priority, prioritySet, *require)
+	default:
+		fmt.Print(usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Printf("Ошибка: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func requireSeason(leagueID int, season string) error {
+	if leagueID == 0 || season == "" {
+		return fmt.Errorf("нужны -league и -season")
+	}
+	return nil
+}
+
+func list(ctx context.Context, leagueID int) error {
+	seasons, err := db.ListLeagueSeasons(ctx, leagueID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%-8s %-8s %-9s %-10s %-30s %s\n", "ЛИГА", "СЕЗОН", "ПРИОРИТЕТ", "ОБРАБОТАН", "НАЗВАНИЕ", "ПОКРЫТИЕ")
+	for _, s := range seasons {
+		name := s.LeagueName
+		if s.Country != "" {
+			name = fmt.Sprintf("%s (%s)", name, s.Country)
+		}
+		fmt.Printf("%-8d %-8s %-9d %-10t %-30s %s\n", s.LeagueID, s.Season, s.Priority, s.IsProcessed, name, strings.Join(coverageList(s.Coverage), ","))
+	}
+	fmt.Printf("Всего сезонов: %d\n", len(seasons))
+	return nil
+}
+
+func updateSeason(ctx context.Context, leagueID int, season string, update func(context.Context, int, string) (bool, error), done string) error {
+	if err := requireSeason(leagueID, season); err != nil {
+		return err
+	}
+	found, err := update(ctx, leagueID, season)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("сезон лиги %d, сезон %s не найден", leagueID, season)
+	}
+	fmt.Printf("Сезон лиги %d, сезон %s %s.\n", leagueID, season, done)
+	return nil
+}
+
+// seed добавляет сезоны лиги из /leagues, у которых есть все требуемые данные.
+// Приоритет уже стоящих в очереди сезонов меняется только при setPriority.
+func seed(ctx context.Context, leagueID int, from string, priority int, setPriority bool, require string) error {
+	if leagueID == 0 {
+		return fmt.Errorf("нужен -league")
+	}
+	var required []string
+	for _, name := range strings.Split(require, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		if _, ok := coverageFlag(models.Coverage{}, name); !ok {
+			return fmt.Errorf("неизвестное покрытие %q", name)
+		}
+		required = append(required, name)
+	}
+
+	provider, err := api.NewProviderFromEnv()
+	if err != nil {
+		return fmt.Errorf("ошибка инициализации провайдера данных: %v", err)
+	}
+	api.EnableArchive(db.PayloadArchive{})
+	seasons, err := provider.FetchLeagueSeasons(ctx, leagueID)
+	if err != nil {
+		return fmt.Errorf("ошибка получения сезонов лиги %d: %v", leagueID, err)
+	}
+
+	added := 0
+	for _, s := range seasons {
+		if from != "" && s.Season < from {
+			continue
+		}
+		if missing := missingCoverage(s.Coverage, required); len(missing) > 0 {
+			fmt.Printf("Сезон %s пропущен: нет покрытия %s\n", s.Season, strings.Join(missing, ","))
+			continue
+		}
+		s.Priority = priority
+		if err := db.SaveLeagueSeason(ctx, s, setPriority); err != nil {
+			return err
+		}
+		added++
+	}
+	fmt.Printf("Лига %d: добавлено или обновлено сезонов %d из %d.\n", leagueID, added, len(seasons))
+	return nil
+}
+
+var coverageNames = []string{"events", "lineups", "statistics", "players", "standings", "injuries", "odds"}
+
+func coverageFlag(c models.Coverage, name string) (bool, bool) {
+	switch name {
+	case "events":
+		return c.Events, true
+	case "lineups":
+		return c.Lineups, true
+	case "statistics":
+		return c.Statistics, true
+	case "players":
+		return c.Players, true
+	case "standings":
+		return c.Standings, true
+	case "injuries":
+		return c.Injuries, true
+	case "odds":
+		return c.Odds, true
+	}
+	return false, false
+}
+
+func coverageList(c models.Coverage) []string {
+	var names []string
+	for _, name := range coverageNames {
+		if ok, _ := coverageFlag(c, name); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func missingCoverage(c models.Coverage, required []string) []string {
+	var missing []string
+	for _, name := range required {
+		if ok, _ := coverageFlag(c, name); !ok {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+)
+
+const defaultLeaseTTL = 2 * time.Minute
+
+// leaseOwner отличает этот процесс от других ингестеров в логах и в ключе аренды.
+var leaseOwner = newLeaseOwner()
+
+func newLeaseOwner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s:%d:%04x", host, os.Getpid(), rand.Intn(1<<16))
+}
+
+// leaseTTL читает LEASE_TTL (например, "2m"): через сколько аренда упавшего процесса освобождается.
+func leaseTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("LEASE_TTL"))
+	if err != nil || ttl <= 0 {
+		return defaultLeaseTTL
+	}
+	return ttl
+}
+
+// acquireSeason берет аренду сезона и продлевает ее каждую треть ttl, пока сезон в работе.
+// Возвращенный контекст отменяется, если аренду продлить не удалось: сезон мог уже
+// перейти к другому процессу. release останавливает продление и снимает аренду.
+func acquireSeason(ctx context.Context, leagueID int, season string) (context.Context, func(), bool) {
+	ttl := leaseTTL()
+	ok, err := matchCache.AcquireLease(ctx, leagueID, season, leaseOwner, ttl)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		return nil, nil, false
+	}
+	if !ok {
+		return nil, nil, false
+	}
+
+	seasonCtx, cancel := context.WithCancel(ctx)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-seasonCtx.Done():
+				return
+			case <-ticker.C:
+				renewed, err := matchCache.RenewLease(seasonCtx, leagueID, season, leaseOwner, ttl)
+				if err != nil && seasonCtx.Err() == nil {
+					// Разовая ошибка Redis не страшна: у аренды есть запас в две трети ttl
+					fmt.Printf("%v\n", err)
+					continue
+				}
+				if !renewed && seasonCtx.Err() == nil {
+					fmt.Printf("Аренда сезона лиги %d, сезон %s потеряна. Останавливаем обработку сезона.\n", leagueID, season)
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	release := func() {
+		cancel()
+		<-stopped
+		if err := matchCache.ReleaseLease(context.WithoutCancel(ctx), leagueID, season, leaseOwner); err != nil {
+			fmt.Printf("%v\n", err)
+		}
+	}
+	return seasonCtx, release, true
+}
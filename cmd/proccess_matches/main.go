@@ -2,48 +2,72 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"football-data-miner/internal/api"
 	"football-data-miner/internal/cache"
 	"football-data-miner/internal/db"
 	"football-data-miner/internal/models"
-	"strconv"
-	"strings"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+var (
+	provider   api.FootballProvider
+	matchCache cache.Cache
+
+	// visited — сезоны, уже взятые за этот запуск. Сезон с несыгранными матчами остается
+	// в очереди и в кэше, и без этой отметки цикл брал бы его снова, не доходя до остальных.
+	visited = make(map[models.Season]bool)
 )
 
 func main() {
-	var shouldExit bool
-	db.InitDB()
+	since := flag.String("since", "", "загружать матчи не раньше этой даты (YYYY-MM-DD, \"YYYY-MM-DD HH:MM:SS\" UTC или RFC3339)")
+	until := flag.String("until", "", "загружать матчи раньше этой даты; более поздние откладываются")
+	windowConfig := flag.String("window-config", "", "JSON с окном дат и переопределениями по лигам (пример: cmd/proccess_matches/window_config.json)")
+	flag.Parse()
+
+	if err := loadWindows(*windowConfig, *since, *until); err != nil {
+		fmt.Printf("Ошибка загрузки окна дат: %v\n", err)
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	watchShutdown(ctx, stop)
+	defer summary.print()
+
+	db.InitDB(ctx)
 	defer db.CloseDB()
 
-	cache.InitRedis()
-	for {
-		if cache.IsCacheEmpty() {
-			fmt.Println("Кэш пустой. Берем следующий сезон из БД...")
-			leagueID, season := db.GetNextUnprocessedSeason()
-			if leagueID == 0 {
-				fmt.Println("Все сезоны обработаны!")
-				break
-			}
-
-			fmt.Printf("Обрабатываем сезон: лига %d, сезон %s\n", leagueID, season)
-			matches, err := api.FetchSeasonMatches(leagueID, season)
-			if err != nil {
-				fmt.Printf("Ошибка при получении матчей: %v\n", err)
-				continue
-			}
-
-			err = cache.CacheSeasonMatches(leagueID, season, matches)
-			if err != nil {
-				fmt.Printf("Ошибка при сохранении матчей в Redis: %v\n", err)
-				continue
-			}
-
-			fmt.Println("Матчи успешно сохранены в Redis. Начинаем обработку...")
-			shouldExit = processMatches(leagueID, season, matches)
-		} else {
-			fmt.Println("Кэш содержит матчи. Продолжаем обработку...")
-			shouldExit = processCachedMatches()
+	var err error
+	provider, err = api.NewProviderFromEnv()
+	if err != nil {
+		fmt.Printf("Ошибка инициализации провайдера данных: %v\n", err)
+		return
+	}
+	api.EnableArchive(db.PayloadArchive{})
+	defer api.PrintUnknownStatistics()
+
+	matchCache, err = cache.NewCacheFromEnv(ctx)
+	if err != nil {
+		fmt.Printf("Ошибка инициализации кэша: %v\n", err)
+		return
+	}
+	defer matchCache.Close()
+
+	fmt.Printf("Процесс %s\n", leaseOwner)
+	for ctx.Err() == nil {
+		shouldExit, claimed := processCachedMatches(ctx)
+		if !claimed {
+			shouldExit, claimed = processNextSeason(ctx)
+		}
+		if !claimed {
+			fmt.Println("Все сезоны обработаны, заняты другими процессами или уже пройдены в этом запуске.")
+			break
 		}
 		if shouldExit {
 			break
@@ -51,103 +75,233 @@ func main() {
 	}
 }
 
-func processMatches(leagueID int, season string, matches []models.Match) bool {
-	totalMatches := len(matches)
-
-	for _, match := range matches {
+// processNextSeason берет из очереди первый сезон, который не арендован другим процессом.
+// Второе значение — удалось ли взять сезон.
+func processNextSeason(ctx context.Context) (bool, bool) {
+	seasons, err := db.GetUnprocessedSeasons(ctx)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		return true, true
+	}
 
-		processed, err := cache.IsMatchProcessed(leagueID, season, match.ID)
-		if err != nil || processed {
+	for _, next := range seasons {
+		if visited[next] {
 			continue
 		}
-		if match.HomeScore == nil {
-			fmt.Print("Shtoto ne tak")
-			return true
+		leagueID, season := next.LeagueID, next.Season
+		seasonCtx, release, ok := acquireSeason(ctx, leagueID, season)
+		if !ok {
+			continue
 		}
+		visited[next] = true
+		shouldExit, claimed := processNewSeason(seasonCtx, leagueID, season)
+		release()
+		if claimed {
+			return leaseResult(ctx, seasonCtx, shouldExit), true
+		}
+	}
+	return false, false
+}
 
-		if match.Date == "" || (match.Date < "2025-05-21 05:05:00" && leagueID != 94 && leagueID != 144) || match.Date < "2025-05-11 05:05:00" {
-			cache.MarkMatchAsProcessed(leagueID, season, match.ID)
+// processNewSeason загружает матчи арендованного сезона в кэш и обрабатывает их.
+// Сезон, который успел завершить другой процесс или который не удалось получить,
+// не считается взятым.
+func processNewSeason(ctx context.Context, leagueID int, season string) (bool, bool) {
+	processed, err := db.IsSeasonProcessed(ctx, leagueID, season)
+	if err != nil || processed {
+		return false, false
+	}
+
+	fmt.Printf("Обрабатываем сезон: лига %d, сезон %s\n", leagueID, season)
+	matches, err := provider.FetchSeasonMatches(ctx, leagueID, season)
+	if err != nil {
+		fmt.Printf("Ошибка при получении матчей: %v\n", err)
+		return false, false
+	}
+
+	err = matchCache.CacheSeasonMatches(ctx, leagueID, season, matches)
+	if err != nil {
+		fmt.Printf("Ошибка при сохранении матчей в кэш: %v\n", err)
+		return false, false
+	}
+
+	enrichTeams(ctx, leagueID, season)
+
+	fmt.Println("Матчи успешно сохранены в кэш. Начинаем обработку...")
+	return processMatches(ctx, leagueID, season, matches), true
+}
+
+// leaseResult не дает потере аренды остановить процесс: если отменен только контекст
+// сезона, процесс переходит к следующему.
+func leaseResult(ctx, seasonCtx context.Context, shouldExit bool) bool {
+	if seasonCtx.Err() != nil && ctx.Err() == nil {
+		return false
+	}
+	return shouldExit
+}
+
+func processMatches(ctx context.Context, leagueID int, season string, matches []models.Match) bool {
+	totalMatches := len(matches)
+	deferred := 0
+	window := windowFor(leagueID)
+	var pending []models.Match
+
+	progress, err := loadProgress(ctx, leagueID, season, matches)
+	if err != nil {
+		fmt.Printf("Ошибка загрузки прогресса: %v\n", err)
+		return true
+	}
+
+	now := time.Now()
+	for _, match := range matches {
+		p := progress[match.ID]
+		if p.IsDone() || p.State == models.ProgressParked {
 			continue
 		}
-		err = db.SaveTeamIfNotExists(match.HomeTeamID, match.HomeTeamName)
-		if err != nil {
-			fmt.Printf("Ошибка сохранения команды %d: %v\n", match.HomeTeamID, err)
+		if p.IsWaiting(now) {
 			continue
 		}
-		err = db.SaveTeamIfNotExists(match.AwayTeamID, match.AwayTeamName)
+
+		kickoff, err := time.Parse(time.RFC3339, match.Date)
 		if err != nil {
-			fmt.Printf("Ошибка сохранения команды %d: %v\n", match.AwayTeamID, err)
+			rejectMatch(ctx, leagueID, season, match.ID, fmt.Errorf("матч ID=%d: неверная дата %q: %v", match.ID, match.Date, err))
 			continue
 		}
-
-		stats, canContinue, err := api.FetchStatistics(match.ID)
-		if err != nil {
-			fmt.Printf("Ошибка статистики: %v\n", err)
+		if window.before(kickoff) {
+			markSkipped(ctx, leagueID, season, match.ID)
 			continue
 		}
-
-		lineups, err := api.FetchLineups(match.ID)
-		if err != nil {
-			fmt.Printf("Ошибка составов: %v\n", err)
+		if window.after(kickoff) {
+			summary.deferred.Add(1)
+			deferred++
 			continue
 		}
-
-		players, err := api.FetchPlayers(match.ID)
-		if err != nil {
-			fmt.Printf("Ошибка событий: %v\n", err)
+		if match.IsCancelled() {
+			fmt.Printf("Матч ID=%d не состоялся (%s). Пропускаем.\n", match.ID, match.Status)
+			markSkipped(ctx, leagueID, season, match.ID)
+			continue
+		}
+		if !match.IsFinished() || match.HomeScore == nil || match.AwayScore == nil {
+			captureOdds(ctx, match)
+			summary.deferred.Add(1)
+			deferred++
 			continue
 		}
+		pending = append(pending, match)
+	}
 
-		parsedStats, _ := api.ParseStatistics(match.ID, stats)
-		parsedLineups := api.MergeLineupAndPlayers(lineups, players, &match)
-		db.SaveMatchDetails(match, leagueID, season, parsedStats, parsedLineups)
-		cache.MarkMatchAsProcessed(leagueID, season, match.ID)
-		if !canContinue {
-			return true
+	runWorkers(ctx, pending, func(match models.Match) {
+		ingestMatch(ctx, leagueID, season, match)
+	})
+	if ctx.Err() != nil {
+		return true
+	}
+
+	progress, err = db.GetSeasonProgress(ctx, leagueID, season)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		return true
+	}
+	snapshotStandings(ctx, leagueID, season, matches, progress)
+
+	done, parked := countSettled(matches, progress)
+	fmt.Printf("Обработано матчей: %d из %d\n", done, totalMatches)
+	if done+parked == totalMatches {
+		if parked > 0 {
+			fmt.Printf("Лига %d, сезон %s: %d матчей отложено после исчерпания попыток, см. dead_letters list.\n", leagueID, season, parked)
 		}
-		isCompleted, err := cache.IsSeasonCompleted(leagueID, season, totalMatches)
-		if isCompleted {
-			fmt.Printf("Сезон лиги %d, сезон %s завершен!\n", leagueID, season)
-			cleanupSeason(leagueID, season)
-			return false
+		fmt.Printf("Сезон лиги %d, сезон %s завершен!\n", leagueID, season)
+		if err := cleanupSeason(ctx, leagueID, season); err != nil {
+			fmt.Printf("%v\n", err)
+			return true
 		}
+		return false
+	}
+	if deferred > 0 {
+		fmt.Printf("Лига %d, сезон %s: %d матчей еще не сыграно или позже окна загрузки. Вернемся к ним при следующем запуске.\n", leagueID, season, deferred)
+		return false
 	}
+	// Остались только неудачные матчи: они повторяются не сразу, а по политике повторов
+	fmt.Printf("Лига %d, сезон %s: матчи с ошибками ждут повторной попытки. Переходим к следующему сезону.\n", leagueID, season)
 	return false
 }
-func processCachedMatches() bool {
-	keys, _ := cache.GetAllSeasonKeys()
-	for _, key := range keys {
-		leagueID, season := parseLeagueAndSeasonFromKey(key)
-		matches, err := cache.GetSeasonMatches(leagueID, season)
+
+// processCachedMatches продолжает сезон, начатый ранее и оставшийся в кэше,
+// если он не арендован другим процессом. Второе значение — удалось ли взять сезон.
+func processCachedMatches(ctx context.Context) (bool, bool) {
+	seasons, err := matchCache.CachedSeasons(ctx)
+	if err != nil {
+		fmt.Printf("Ошибка получения сезонов из кэша: %v\n", err)
+		return true, true
+	}
+	for _, cached := range seasons {
+		if visited[cached] {
+			continue
+		}
+		leagueID, season := cached.LeagueID, cached.Season
+		seasonCtx, release, ok := acquireSeason(ctx, leagueID, season)
+		if !ok {
+			continue
+		}
+		visited[cached] = true
+
+		matches, err := matchCache.GetSeasonMatches(seasonCtx, leagueID, season)
 		if err != nil {
+			release()
 			fmt.Printf("Ошибка при получении матчей: %v\n", err)
 			continue
 		}
-		return processMatches(leagueID, season, matches)
+		fmt.Printf("Продолжаем сезон из кэша: лига %d, сезон %s\n", leagueID, season)
+		matches = refreshPendingSeason(seasonCtx, leagueID, season, matches)
+		shouldExit := processMatches(seasonCtx, leagueID, season, matches)
+		release()
+		return leaseResult(ctx, seasonCtx, shouldExit), true
 	}
-	return false
+	return false, false
 }
 
-func parseLeagueAndSeasonFromKey(key string) (int, string) {
-	parts := strings.Split(key, ":")
-	leagueID, _ := strconv.Atoi(parts[2])
-	return leagueID, parts[3]
-}
+// refreshPendingSeason перезапрашивает матчи сезона, если в кэше остались несыгранные:
+// их статус и счет могли измениться с момента кэширования.
+func refreshPendingSeason(ctx context.Context, leagueID int, season string, matches []models.Match) []models.Match {
+	progress, err := db.GetSeasonProgress(ctx, leagueID, season)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		return matches
+	}
+	for _, match := range matches {
+		if !match.IsPending() || progress[match.ID].IsDone() {
+			continue
+		}
 
-func cleanupSeason(leagueID int, season string) {
-	cacheKey := cache.GetSeasonKey(leagueID, season)
-	processedKey := cache.GetProcessedKey(leagueID, season)
+		fresh, err := provider.FetchSeasonMatches(ctx, leagueID, season)
+		if err != nil {
+			fmt.Printf("Ошибка обновления матчей сезона: %v\n", err)
+			return matches
+		}
+		if err := matchCache.CacheSeasonMatches(ctx, leagueID, season, fresh); err != nil {
+			fmt.Printf("Ошибка при сохранении матчей в кэш: %v\n", err)
+		}
+		return fresh
+	}
+	return matches
+}
 
-	err := cache.Rdb.Del(context.Background(), cacheKey, processedKey).Err()
-	if err != nil {
+// cleanupSeason отмечает сезон обработанным и очищает его кэш. Если отметить не удалось,
+// кэш остается: сезон будет продолжен из него при следующем запуске.
+func cleanupSeason(ctx context.Context, leagueID int, season string) error {
+	if err := db.MarkSeasonAsProcessed(ctx, leagueID, season); err != nil {
+		return err
+	}
+	if err := matchCache.ClearSeason(ctx, leagueID, season); err != nil {
 		fmt.Printf("Ошибка очистки кэша: %v\n", err)
 	} else {
 		fmt.Println("Кэш очищен.")
 	}
-	err = db.MarkSeasonAsProcessed(leagueID, season)
+	return nil
 }
-func RecheckAndCacheMissingMatches() {
-	missingMatches, err := db.GetMissingMatchesFromDB(db.DB)
+
+func RecheckAndCacheMissingMatches(ctx context.Context) {
+	missingMatches, err := db.GetMissingMatchesFromDB(ctx, db.DB)
 	if err != nil {
 		fmt.Printf("Ошибка получения недостающих матчей: %v\n", err)
 		return
@@ -161,7 +315,10 @@ func RecheckAndCacheMissingMatches() {
 	fmt.Printf("Найдено %d недостающих матчей для обработки.\n", len(missingMatches))
 
 	for _, match := range missingMatches {
-		leagueID, season, err := db.GetLeagueAndSeasonForMatch(match.ID)
+		if ctx.Err() != nil {
+			return
+		}
+		leagueID, season, err := db.GetLeagueAndSeasonForMatch(ctx, match.ID)
 		if err != nil {
 			fmt.Printf("Ошибка получения лиги и сезона для матча ID=%d: %v\n", match.ID, err)
 			continue
@@ -169,48 +326,6 @@ func RecheckAndCacheMissingMatches() {
 
 		fmt.Printf("Обрабатываем матч ID=%d (лига %d, сезон %s)\n", match.ID, leagueID, season)
 
-		stats, canContinue, err := api.FetchStatistics(match.ID)
-		if err != nil {
-			fmt.Printf("Ошибка статистики для матча ID=%d: %v\n", match.ID, err)
-			continue
-		}
-
-		lineups, err := api.FetchLineups(match.ID)
-		if err != nil {
-			fmt.Printf("Ошибка составов для матча ID=%d: %v\n", match.ID, err)
-			continue
-		}
-
-		players, err := api.FetchPlayers(match.ID)
-		if err != nil {
-			fmt.Printf("Ошибка событий для матча ID=%d: %v\n", match.ID, err)
-			continue
-		}
-
-		parsedStats, _ := api.ParseStatistics(match.ID, stats)
-		parsedLineups := api.MergeLineupAndPlayers(lineups, players, &match)
-		db.SaveMatchDetails(match, leagueID, season, parsedStats, parsedLineups)
-
-		cache.MarkMatchAsProcessed(leagueID, season, match.ID)
-		if !canContinue {
-			fmt.Printf("Прерываем обработку после матча ID=%d\n", match.ID)
-			break
-		}
-	}
-}
-
-func MarkProcessedMatchesInRedis(leagueID int, season string) error {
-	fmt.Println("Получение списка обработанных матчей из БД...")
-	processedMatches, err := db.GetProcessedMatches(leagueID, season)
-	if err != nil {
-		return fmt.Errorf("ошибка получения обработанных матчей из БД: %v", err)
+		ingestMatch(ctx, leagueID, season, match)
 	}
-
-	fmt.Printf("Найдено %d обработанных матчей для кэширования в Redis.\n", len(processedMatches))
-	for _, match := range processedMatches {
-		cache.MarkMatchAsProcessed(leagueID, season, match)
-	}
-
-	fmt.Println("Все обработанные матчи успешно сохранены в Redis.")
-	return nil
 }
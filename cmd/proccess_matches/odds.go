@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"football-data-miner/internal/api"
+	"football-data-miner/internal/db"
+	"football-data-miner/internal/models"
+	"os"
+	"strconv"
+	"time"
+)
+
+// За сколько до начала api-sports публикует коэффициенты.
+const oddsHorizon = 14 * 24 * time.Hour
+
+// captureOdds снимает коэффициенты несыгранного матча: первый снимок — как только
+// матч попадает в горизонт публикации, второй — за ODDS_CLOSING_HOURS часов до начала
+// (если переменная задана).
+func captureOdds(ctx context.Context, match models.Match) {
+	if match.Status != models.StatusNotStarted {
+		return
+	}
+	kickoff, err := time.Parse(time.RFC3339, match.Date)
+	if err != nil {
+		return
+	}
+	untilKickoff := time.Until(kickoff)
+	if untilKickoff <= 0 || untilKickoff > oddsHorizon {
+		return
+	}
+
+	last, err := db.GetLastOddsCapture(ctx, match.ID)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		return
+	}
+	closing := closingWindow()
+	needClosing := closing > 0 && untilKickoff <= closing && (last == nil || kickoff.Sub(*last) > closing)
+	if last != nil && !needClosing {
+		return
+	}
+
+	odds, err := provider.FetchOdds(ctx, match.ID)
+	if errors.Is(err, api.ErrNotFound) || (err == nil && len(odds) == 0) {
+		return
+	}
+	if err != nil {
+		fmt.Printf("Ошибка получения коэффициентов матча ID=%d: %v\n", match.ID, err)
+		return
+	}
+	if err := db.SaveOdds(ctx, odds); err != nil {
+		fmt.Printf("%v\n", err)
+		return
+	}
+	fmt.Printf("Матч ID=%d: сохранено коэффициентов %d\n", match.ID, len(odds))
+}
+
+// closingWindow читает ODDS_CLOSING_HOURS; 0 — снимок перед началом отключен.
+func closingWindow() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("ODDS_CLOSING_HOURS"))
+	if err != nil || hours < 0 {
+		return 0
+	}
+	return time.Duration(hours) * time.Hour
+}
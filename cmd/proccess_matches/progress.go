@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"football-data-miner/internal/api"
+	"football-data-miner/internal/db"
+	"football-data-miner/internal/models"
+	"time"
+)
+
+// loadProgress регистрирует матчи сезона в ingestion_progress и возвращает их состояния.
+// Прогресс в БД — источник истины: кэш может быть очищен или истечь посреди сезона.
+func loadProgress(ctx context.Context, leagueID int, season string, matches []models.Match) (map[int]models.IngestionProgress, error) {
+	ids := make([]int, len(matches))
+	for i, match := range matches {
+		ids[i] = match.ID
+	}
+	if err := db.AddPendingMatches(ctx, leagueID, season, ids); err != nil {
+		return nil, err
+	}
+	return db.GetSeasonProgress(ctx, leagueID, season)
+}
+
+// countSettled считает матчи сезона, которых сезон больше не ждет: в конечном состоянии
+// и отложенные до решения оператора (parked).
+func countSettled(matches []models.Match, progress map[int]models.IngestionProgress) (done, parked int) {
+	for _, match := range matches {
+		switch p := progress[match.ID]; {
+		case p.IsDone():
+			done++
+		case p.State == models.ProgressParked:
+			parked++
+		}
+	}
+	return done, parked
+}
+
+// ingestMatch загружает один матч: запрашивает данные, сохраняет и записывает состояние
+// в ingestion_progress. Матч, отмеченный в кэше как обработанный, не трогается.
+// Прерванный сигналом матч остается в прежнем состоянии.
+func ingestMatch(ctx context.Context, leagueID int, season string, match models.Match) {
+	// Быстрый путь: матч мог сохранить другой процесс уже после загрузки прогресса сезона
+	if processed, err := matchCache.IsMatchProcessed(ctx, leagueID, season, match.ID); err == nil && processed {
+		return
+	}
+	attempt, err := db.StartMatchAttempt(ctx, leagueID, season, match.ID)
+	if err != nil {
+		if ctx.Err() != nil {
+			summary.interrupted.Add(1)
+			return
+		}
+		summary.failed.Add(1)
+		fmt.Printf("%v\n", err)
+		return
+	}
+
+	details, err := fetchMatchDetails(ctx, match.ID)
+	if err != nil {
+		if ctx.Err() != nil {
+			summary.interrupted.Add(1)
+			return
+		}
+		markFailed(ctx, leagueID, season, match.ID, attempt, err)
+		return
+	}
+	if err := db.SetMatchState(ctx, leagueID, season, match.ID, models.ProgressFetched, ""); err != nil {
+		fmt.Printf("%v\n", err)
+	}
+	storeMatch(ctx, leagueID, season, match, attempt, details)
+}
+
+// storeMatch разбирает данные матча, сохраняет его одной транзакцией вместе с отметкой
+// saved в ingestion_progress и дублирует отметку в кэш.
+// Сохранение не прерывается сигналом: данные уже получены, а транзакция ограничена
+// таймаутом БД. Если сигнал пришел раньше, до сюда матч не доходит и остается необработанным.
+func storeMatch(ctx context.Context, leagueID int, season string, match models.Match, attempt int, details matchDetails) {
+	ctx = context.WithoutCancel(ctx)
+
+	parsedStats := api.ParseMatchStatistics(leagueID, match, details.stats)
+	parsedLineups := api.MergeLineupAndPlayers(details.lineups, details.players, &match)
+	err := db.SaveMatchDetails(ctx, match, leagueID, season, parsedStats, parsedLineups, details.events, details.absences)
+	if errors.Is(err, db.ErrMatchExists) {
+		// Матч сохранен раньше, но прогресс об этом не знал
+		err = db.SetMatchState(ctx, leagueID, season, match.ID, models.ProgressSaved, "")
+	}
+	if err != nil {
+		markFailed(ctx, leagueID, season, match.ID, attempt, err)
+		return
+	}
+	summary.saved.Add(1)
+	matchCache.MarkMatchAsProcessed(ctx, leagueID, season, match.ID)
+}
+
+// markSkipped отмечает матч, который загружать не нужно (вне окна или не состоялся).
+func markSkipped(ctx context.Context, leagueID int, season string, matchID int) {
+	summary.skipped.Add(1)
+	if err := db.SetMatchState(ctx, leagueID, season, matchID, models.ProgressSkipped, ""); err != nil {
+		fmt.Printf("%v\n", err)
+		return
+	}
+	matchCache.MarkMatchAsProcessed(ctx, leagueID, season, matchID)
+}
+
+// rejectMatch засчитывает неудачную попытку матчу, который нельзя даже начать загружать
+// (например, с неразбираемой датой): он проходит политику повторов и в итоге попадает
+// в dead_letters, а не пропускается молча.
+func rejectMatch(ctx context.Context, leagueID int, season string, matchID int, cause error) {
+	attempt, err := db.StartMatchAttempt(ctx, leagueID, season, matchID)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		return
+	}
+	markFailed(ctx, leagueID, season, matchID, attempt, cause)
+}
+
+// markFailed записывает причину неудачи и по политике повторов либо планирует следующую
+// попытку, либо откладывает матч в parked, чтобы он не держал сезон.
+func markFailed(ctx context.Context, leagueID int, season string, matchID, attempt int, cause error) {
+	summary.failed.Add(1)
+	retryAt := nextRetry(attempt)
+	if retryAt != nil {
+		fmt.Printf("%v (попытка %d, повтор после %s)\n", cause, attempt, retryAt.Format(time.RFC3339))
+	} else {
+		summary.parked.Add(1)
+		fmt.Printf("%v (попытка %d, попытки исчерпаны — матч отложен)\n", cause, attempt)
+	}
+	if err := db.FailMatch(context.WithoutCancel(ctx), leagueID, season, matchID, cause.Error(), retryAt); err != nil {
+		fmt.Printf("%v\n", err)
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMaxAttempts = 5
+	defaultRetryDelay  = 15 * time.Minute
+	maxRetryDelay      = 24 * time.Hour
+)
+
+// maxMatchAttempts — сколько раз пробовать загрузить матч, прежде чем отложить его
+// до решения оператора (MAX_MATCH_ATTEMPTS).
+func maxMatchAttempts() int {
+	n, err := strconv.Atoi(os.Getenv("MAX_MATCH_ATTEMPTS"))
+	if err != nil || n < 1 {
+		return defaultMaxAttempts
+	}
+	return n
+}
+
+// nextRetry возвращает время следующей попытки после неудачной попытки номер attempt
+// или nil, если попытки исчерпаны. Пауза удваивается от MATCH_RETRY_DELAY до суток.
+func nextRetry(attempt int) *time.Time {
+	if attempt >= maxMatchAttempts() {
+		return nil
+	}
+	delay, err := time.ParseDuration(os.Getenv("MATCH_RETRY_DELAY"))
+	if err != nil || delay <= 0 {
+		delay = defaultRetryDelay
+	}
+	for i := 1; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	retryAt := time.Now().Add(delay)
+	return &retryAt
+}
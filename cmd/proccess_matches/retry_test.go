@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextRetry(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxAttempts string
+		delay       string
+		attempt     int
+		want        time.Duration // 0 — попытки исчерпаны
+	}{
+		{"первая неудача", "", "", 1, defaultRetryDelay},
+		{"пауза удваивается", "", "", 3, 4 * defaultRetryDelay},
+		{"последняя попытка", "", "", defaultMaxAttempts, 0},
+		{"MATCH_RETRY_DELAY", "", "1m", 2, 2 * time.Minute},
+		{"не больше суток", "20", "10h", 4, maxRetryDelay},
+		{"некорректная задержка", "", "soon", 1, defaultRetryDelay},
+		{"MAX_MATCH_ATTEMPTS", "2", "", 2, 0},
+		{"некорректный MAX_MATCH_ATTEMPTS", "0", "", 4, 8 * defaultRetryDelay},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MAX_MATCH_ATTEMPTS", tt.maxAttempts)
+			t.Setenv("MATCH_RETRY_DELAY", tt.delay)
+
+			start := time.Now()
+			retryAt := nextRetry(tt.attempt)
+			if tt.want == 0 {
+				if retryAt != nil {
+					t.Fatalf("nextRetry(%d) = %s, ожидалось nil", tt.attempt, retryAt)
+				}
+				return
+			}
+			if retryAt == nil {
+				t.Fatalf("nextRetry(%d) = nil, ожидалась пауза %s", tt.attempt, tt.want)
+			}
+			if got := retryAt.Sub(start); got < tt.want || got > tt.want+time.Second {
+				t.Errorf("nextRetry(%d): пауза %s, ожидалось %s", tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
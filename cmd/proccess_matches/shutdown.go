@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// ingestSummary — счетчики матчей за запуск, печатаются при выходе.
+type ingestSummary struct {
+	saved       atomic.Int64
+	failed      atomic.Int64
+	skipped     atomic.Int64
+	deferred    atomic.Int64
+	parked      atomic.Int64
+	interrupted atomic.Int64
+}
+
+var summary ingestSummary
+
+func (s *ingestSummary) print() {
+	fmt.Printf("Итог: сохранено %d, с ошибками %d (из них в parked %d), пропущено %d, отложено %d, прервано %d\n",
+		s.saved.Load(), s.failed.Load(), s.parked.Load(), s.skipped.Load(), s.deferred.Load(), s.interrupted.Load())
+}
+
+// watchShutdown сообщает о полученном SIGINT/SIGTERM и снимает перехват сигналов:
+// повторный Ctrl-C завершает процесс сразу, не дожидаясь сохранения текущих матчей.
+func watchShutdown(ctx context.Context, stop context.CancelFunc) {
+	go func() {
+		<-ctx.Done()
+		stop()
+		fmt.Println("Получен сигнал завершения. Дописываем начатые матчи, остальные откладываем до следующего запуска...")
+	}()
+}
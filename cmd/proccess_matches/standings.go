@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"football-data-miner/internal/api"
+	"football-data-miner/internal/db"
+	"football-data-miner/internal/models"
+	"time"
+)
+
+// seasonRound — тур сезона и время начала последнего сыгранного в нем матча.
+type seasonRound struct {
+	name string
+	end  time.Time
+}
+
+// snapshotStandings сохраняет таблицу после последнего полностью обработанного тура.
+// API отдает только текущую таблицу, поэтому снимок делается, только если число
+// сыгранных командами матчей совпадает с этим туром. Более ранние туры без снимка
+// не перебираются: их таблицу уже не получить, и повторные попытки бесполезны.
+func snapshotStandings(ctx context.Context, leagueID int, season string, matches []models.Match, progress map[int]models.IngestionProgress) {
+	round, ok := lastProcessedRound(matches, progress)
+	if !ok {
+		return
+	}
+
+	exists, err := db.HasStandingsSnapshot(ctx, leagueID, season, round.name)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		return
+	}
+	if exists {
+		return
+	}
+
+	rows, err := provider.FetchStandings(ctx, leagueID, season)
+	if errors.Is(err, api.ErrNotFound) || (err == nil && len(rows) == 0) {
+		return
+	}
+	if err != nil {
+		fmt.Printf("Ошибка получения таблицы лиги %d, сезон %s: %v\n", leagueID, season, err)
+		return
+	}
+
+	if !standingsAfter(rows, matches, round.end) {
+		fmt.Printf("Лига %d, сезон %s: таблица не соответствует туру %q, снимок пропущен\n", leagueID, season, round.name)
+		return
+	}
+	for i := range rows {
+		rows[i].Round = round.name
+	}
+	if err := db.SaveStandingsSnapshot(ctx, rows); err != nil {
+		fmt.Printf("Ошибка сохранения таблицы лиги %d, сезон %s: %v\n", leagueID, season, err)
+		return
+	}
+	fmt.Printf("Сохранена таблица лиги %d, сезон %s после тура %q\n", leagueID, season, round.name)
+}
+
+// lastProcessedRound возвращает последний завершившийся тур, все матчи которого
+// сыграны и обработаны. Отмененные и перенесенные матчи тур не держат.
+func lastProcessedRound(matches []models.Match, progress map[int]models.IngestionProgress) (seasonRound, bool) {
+	ends := make(map[string]time.Time)
+	open := make(map[string]bool)
+	for _, match := range matches {
+		if match.Round == "" || match.IsCancelled() || match.Status == models.StatusPostponed {
+			continue
+		}
+		kickoff, err := time.Parse(time.RFC3339, match.Date)
+		if err != nil || !match.IsFinished() || !progress[match.ID].IsDone() {
+			open[match.Round] = true
+			continue
+		}
+		if kickoff.After(ends[match.Round]) {
+			ends[match.Round] = kickoff
+		}
+	}
+
+	var last seasonRound
+	for name, end := range ends {
+		if !open[name] && end.After(last.end) {
+			last = seasonRound{name: name, end: end}
+		}
+	}
+	return last, last.name != ""
+}
+
+// standingsAfter проверяет, что таблица отражает положение сразу после матчей,
+// начавшихся не позже until: у каждой команды столько игр, сколько сыграно к этому
+// времени внутри ее группы (матчи плей-офф в таблицу не входят).
+func standingsAfter(rows []models.StandingRow, matches []models.Match, until time.Time) bool {
+	groups := make(map[int]string, len(rows))
+	for _, row := range rows {
+		groups[row.TeamID] = row.Group
+	}
+
+	played := make(map[int]int)
+	for _, match := range matches {
+		if !match.IsFinished() {
+			continue
+		}
+		kickoff, err := time.Parse(time.RFC3339, match.Date)
+		if err != nil || kickoff.After(until) {
+			continue
+		}
+		home, homeOK := groups[match.HomeTeamID]
+		away, awayOK := groups[match.AwayTeamID]
+		if !homeOK || !awayOK || home != away {
+			continue
+		}
+		played[match.HomeTeamID]++
+		played[match.AwayTeamID]++
+	}
+
+	for _, row := range rows {
+		if row.Played != played[row.TeamID] {
+			return false
+		}
+	}
+	return true
+}
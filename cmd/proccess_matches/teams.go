@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"football-data-miner/internal/db"
+)
+
+// enrichTeams сохраняет расширенные данные команд сезона и их стадионы из /teams.
+func enrichTeams(ctx context.Context, leagueID int, season string) {
+	teams, venues, err := provider.FetchTeams(ctx, leagueID, season)
+	if err != nil {
+		fmt.Printf("Ошибка получения команд лиги %d, сезон %s: %v\n", leagueID, season, err)
+		return
+	}
+
+	for _, venue := range venues {
+		if err := db.SaveVenue(ctx, venue); err != nil {
+			fmt.Printf("Ошибка сохранения стадиона %d: %v\n", venue.ID, err)
+		}
+	}
+	for _, team := range teams {
+		if err := db.SaveTeam(ctx, team); err != nil {
+			fmt.Printf("Ошибка сохранения команды %d: %v\n", team.ID, err)
+		}
+	}
+	fmt.Printf("Сохранено команд: %d, стадионов: %d\n", len(teams), len(venues))
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Форматы границ окна в флагах и конфиге: дата, дата со временем (UTC) или RFC3339.
+var windowLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"}
+
+// dateWindow — интервал дат матчей, которые загружаются. Нулевая граница не ограничивает.
+type dateWindow struct {
+	since time.Time
+	until time.Time
+}
+
+// WindowConfig — файл -window-config: общее окно и переопределения по лигам.
+// Граница лиги, не заданная в переопределении, берется из общего окна.
+type WindowConfig struct {
+	Since   string                  `json:"since"`
+	Until   string                  `json:"until"`
+	Leagues map[string]WindowBounds `json:"leagues"`
+}
+
+type WindowBounds struct {
+	Since string `json:"since"`
+	Until string `json:"until"`
+}
+
+var (
+	defaultWindow dateWindow
+	leagueWindows = map[int]dateWindow{}
+)
+
+// loadWindows собирает окна из конфига и флагов; флаги --since/--until заменяют общее окно конфига.
+func loadWindows(configPath, since, until string) error {
+	var config WindowConfig
+	if configPath != "" {
+		file, err := os.ReadFile(configPath)
+		if err != nil {
+			return fmt.Errorf("ошибка чтения файла: %v", err)
+		}
+		if err := json.Unmarshal(file, &config); err != nil {
+			return fmt.Errorf("ошибка парсинга JSON: %v", err)
+		}
+	}
+	if since != "" {
+		config.Since = since
+	}
+	if until != "" {
+		config.Until = until
+	}
+
+	var err error
+	defaultWindow, err = parseWindow(WindowBounds{Since: config.Since, Until: config.Until}, dateWindow{})
+	if err != nil {
+		return err
+	}
+	for key, bounds := range config.Leagues {
+		leagueID, err := strconv.Atoi(key)
+		if err != nil {
+			return fmt.Errorf("некорректный ID лиги %q: %v", key, err)
+		}
+		window, err := parseWindow(bounds, defaultWindow)
+		if err != nil {
+			return fmt.Errorf("лига %d: %v", leagueID, err)
+		}
+		leagueWindows[leagueID] = window
+	}
+	return nil
+}
+
+func parseWindow(bounds WindowBounds, fallback dateWindow) (dateWindow, error) {
+	window := fallback
+	var err error
+	if bounds.Since != "" {
+		if window.since, err = parseWindowTime(bounds.Since); err != nil {
+			return window, err
+		}
+	}
+	if bounds.Until != "" {
+		if window.until, err = parseWindowTime(bounds.Until); err != nil {
+			return window, err
+		}
+	}
+	if !window.since.IsZero() && !window.until.IsZero() && !window.since.Before(window.until) {
+		return window, fmt.Errorf("начало окна %s не раньше конца %s", bounds.Since, bounds.Until)
+	}
+	return window, nil
+}
+
+func parseWindowTime(value string) (time.Time, error) {
+	for _, layout := range windowLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("некорректная дата %q: ожидается YYYY-MM-DD, \"YYYY-MM-DD HH:MM:SS\" или RFC3339", value)
+}
+
+func windowFor(leagueID int) dateWindow {
+	if window, ok := leagueWindows[leagueID]; ok {
+		return window
+	}
+	return defaultWindow
+}
+
+// before — матч раньше окна: он уже не нужен и отмечается обработанным.
+func (w dateWindow) before(t time.Time) bool {
+	return !w.since.IsZero() && t.Before(w.since)
+}
+
+// after — матч позже окна: он откладывается до запуска с более поздним --until.
+func (w dateWindow) after(t time.Time) bool {
+	return !w.until.IsZero() && !t.Before(w.until)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWindow(t *testing.T) {
+	date := func(value string) time.Time {
+		t.Helper()
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	fallback := dateWindow{since: date("2020-07-01T00:00:00Z"), until: date("2024-07-01T00:00:00Z")}
+
+	tests := []struct {
+		name     string
+		bounds   WindowBounds
+		fallback dateWindow
+		want     dateWindow
+		wantErr  bool
+	}{
+		{"без границ", WindowBounds{}, dateWindow{}, dateWindow{}, false},
+		{"дата", WindowBounds{Since: "2023-08-01"}, dateWindow{}, dateWindow{since: date("2023-08-01T00:00:00Z")}, false},
+		{"дата со временем", WindowBounds{Until: "2024-05-19 15:00:00"}, dateWindow{}, dateWindow{until: date("2024-05-19T15:00:00Z")}, false},
+		{"RFC3339 со смещением", WindowBounds{Since: "2023-08-11T20:00:00+01:00"}, dateWindow{}, dateWindow{since: date("2023-08-11T19:00:00Z")}, false},
+		{"границы из общего окна", WindowBounds{Since: "2022-07-01"}, fallback, dateWindow{since: date("2022-07-01T00:00:00Z"), until: fallback.until}, false},
+		{"некорректная дата", WindowBounds{Since: "01.08.2023"}, dateWindow{}, dateWindow{}, true},
+		{"начало не раньше конца", WindowBounds{Since: "2024-01-01", Until: "2024-01-01"}, dateWindow{}, dateWindow{}, true},
+		{"конец раньше начала общего окна", WindowBounds{Until: "2019-01-01"}, fallback, dateWindow{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseWindow(tt.bounds, tt.fallback)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseWindow(%+v) без ошибки, ожидалась ошибка", tt.bounds)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseWindow(%+v): %v", tt.bounds, err)
+			}
+			if !got.since.Equal(tt.want.since) || !got.until.Equal(tt.want.until) {
+				t.Errorf("parseWindow(%+v) = %s — %s, ожидалось %s — %s", tt.bounds, got.since, got.until, tt.want.since, tt.want.until)
+			}
+		})
+	}
+}
+
+func TestDateWindowBounds(t *testing.T) {
+	window := dateWindow{
+		since: time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC),
+		until: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+	}
+	tests := []struct {
+		name          string
+		kickoff       time.Time
+		before, after bool
+	}{
+		{"раньше окна", time.Date(2023, 7, 31, 23, 59, 0, 0, time.UTC), true, false},
+		{"начало окна", window.since, false, false},
+		{"внутри окна", time.Date(2024, 1, 1, 15, 0, 0, 0, time.UTC), false, false},
+		{"конец окна не включается", window.until, false, true},
+	}
+	for _, tt := range tests {
+		if got := window.before(tt.kickoff); got != tt.before {
+			t.Errorf("%s: before = %t, ожидалось %t", tt.name, got, tt.before)
+		}
+		if got := window.after(tt.kickoff); got != tt.after {
+			t.Errorf("%s: after = %t, ожидалось %t", tt.name, got, tt.after)
+		}
+	}
+	if open := (dateWindow{}); open.before(window.since) || open.after(window.until) {
+		t.Errorf("окно без границ не должно отсекать матчи")
+	}
+}
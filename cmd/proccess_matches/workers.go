@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"football-data-miner/internal/api"
+	"football-data-miner/internal/models"
+	"os"
+	"strconv"
+	"sync"
+)
+
+const defaultWorkers = 4
+
+// matchDetails — данные эндпоинтов по одному матчу.
+type matchDetails struct {
+	stats    []api.TeamStatistics
+	lineups  api.LineupResponse
+	players  api.PlayersResponse
+	events   []models.MatchEvent
+	absences []models.PlayerAbsence
+}
+
+// fetchMatchDetails параллельно запрашивает все эндпоинты матча. Темп запросов
+// задает общий лимитер api, поэтому параллельность не выходит за квоту.
+// ErrNotFound не считается ошибкой — у матча просто нет этих данных.
+func fetchMatchDetails(ctx context.Context, matchID int) (matchDetails, error) {
+	var details matchDetails
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+
+	wg.Add(5)
+	go func() {
+		defer wg.Done()
+		details.stats, errs[0] = provider.FetchStatistics(ctx, matchID)
+	}()
+	go func() {
+		defer wg.Done()
+		details.lineups, errs[1] = provider.FetchLineups(ctx, matchID)
+	}()
+	go func() {
+		defer wg.Done()
+		details.players, errs[2] = provider.FetchPlayers(ctx, matchID)
+	}()
+	go func() {
+		defer wg.Done()
+		details.events, errs[3] = provider.FetchEvents(ctx, matchID)
+	}()
+	go func() {
+		defer wg.Done()
+		details.absences, errs[4] = provider.FetchInjuries(ctx, matchID)
+	}()
+	wg.Wait()
+
+	names := []string{"статистики", "составов", "игроков", "событий", "травм"}
+	for i, err := range errs {
+		if err != nil && !errors.Is(err, api.ErrNotFound) {
+			return details, fmt.Errorf("ошибка %s для матча ID=%d: %w", names[i], matchID, err)
+		}
+	}
+	return details, nil
+}
+
+// runWorkers обрабатывает матчи пулом из workerCount() горутин.
+func runWorkers(ctx context.Context, matches []models.Match, handle func(match models.Match)) {
+	jobs := make(chan models.Match)
+	var wg sync.WaitGroup
+
+	for i := 0; i < workerCount(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for match := range jobs {
+				handle(match)
+			}
+		}()
+	}
+	// После отмены ctx новые матчи не раздаются, начатые дорабатываются.
+	for _, match := range matches {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- match
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// workerCount берет число параллельно обрабатываемых матчей из INGEST_WORKERS.
+func workerCount() int {
+	n, err := strconv.Atoi(os.Getenv("INGEST_WORKERS"))
+	if err != nil || n < 1 {
+		return defaultWorkers
+	}
+	return n
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"football-data-miner/internal/api"
+	"football-data-miner/internal/db"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Повторно разбирает архивные ответы API из raw_payloads и перезаписывает
+// match_statistics и lineups, не расходуя квоту запросов.
+func main() {
+	leagueID := flag.Int("league", 0, "ID лиги (0 — все)")
+	season := flag.String("season", "", "сезон (пусто — все)")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db.InitDB(ctx)
+	defer db.CloseDB()
+
+	matches, err := db.GetArchivedMatches(ctx, *leagueID, *season)
+	if err != nil {
+		fmt.Printf("Ошибка получения матчей: %v\n", err)
+		return
+	}
+	fmt.Printf("Найдено %d матчей с архивом ответов.\n", len(matches))
+
+	provider := api.NewArchiveProvider(db.PayloadArchive{})
+	var reprocessed, failed int
+	for _, match := range matches {
+		if ctx.Err() != nil {
+			break
+		}
+		stats, err := provider.FetchStatistics(ctx, match.ID)
+		if err != nil {
+			fmt.Printf("Ошибка статистики для матча ID=%d: %v\n", match.ID, err)
+			failed++
+			continue
+		}
+
+		lineups, err := provider.FetchLineups(ctx, match.ID)
+		if err != nil && !errors.Is(err, api.ErrNotFound) {
+			fmt.Printf("Ошибка составов для матча ID=%d: %v\n", match.ID, err)
+			failed++
+			continue
+		}
+
+		players, err := provider.FetchPlayers(ctx, match.ID)
+		if err != nil && !errors.Is(err, api.ErrNotFound) {
+			fmt.Printf("Ошибка игроков для матча ID=%d: %v\n", match.ID, err)
+			failed++
+			continue
+		}
+
+		parsedStats := api.ParseMatchStatistics(match.LeagueID, match, stats)
+		parsedLineups := api.MergeLineupAndPlayers(lineups, players, &match)
+		if err := db.ReplaceMatchDetails(ctx, match, parsedStats, parsedLineups); err != nil {
+			fmt.Printf("Ошибка перезаписи матча ID=%d: %v\n", match.ID, err)
+			failed++
+			continue
+		}
+		reprocessed++
+	}
+
+	fmt.Printf("Переобработано матчей: %d, с ошибками: %d\n", reprocessed, failed)
+	api.PrintUnknownStatistics()
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"football-data-miner/internal/cache"
+	"football-data-miner/internal/db"
+	"football-data-miner/internal/models"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Восстанавливает множества обработанных матчей в кэше по ingestion_progress:
+// после очистки Redis или истечения TTL посреди сезона.
+func main() {
+	leagueID := flag.Int("league", 0, "ID лиги (0 — все сезоны в кэше)")
+	season := flag.String("season", "", "сезон (вместе с -league)")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db.InitDB(ctx)
+	defer db.CloseDB()
+
+	matchCache, err := cache.NewCacheFromEnv(ctx)
+	if err != nil {
+		fmt.Printf("Ошибка инициализации кэша: %v\n", err)
+		return
+	}
+	defer matchCache.Close()
+
+	var seasons []models.Season
+	if *leagueID != 0 && *season != "" {
+		seasons = []models.Season{{LeagueID: *leagueID, Season: *season}}
+	} else {
+		seasons, err = matchCache.CachedSeasons(ctx)
+		if err != nil {
+			fmt.Printf("Ошибка получения сезонов из кэша: %v\n", err)
+			return
+		}
+	}
+
+	for _, s := range seasons {
+		if ctx.Err() != nil {
+			break
+		}
+		done, err := db.GetDoneMatches(ctx, s.LeagueID, s.Season)
+		if err != nil {
+			fmt.Printf("%v\n", err)
+			continue
+		}
+		if err := matchCache.SetProcessedMatches(ctx, s.LeagueID, s.Season, done); err != nil {
+			fmt.Printf("Ошибка синхронизации лиги %d, сезон %s: %v\n", s.LeagueID, s.Season, err)
+			continue
+		}
+		fmt.Printf("Лига %d, сезон %s: в кэше %d обработанных матчей.\n", s.LeagueID, s.Season, len(done))
+	}
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"football-data-miner/internal/models"
+	"io"
+)
+
+// Эндпоинты api-sports по отдельному матчу; они же — ключи архива ответов по матчам.
+const (
+	endpointStatistics = "fixtures/statistics"
+	endpointLineups    = "fixtures/lineups"
+	endpointPlayers    = "fixtures/players"
+	endpointEvents     = "fixtures/events"
+)
+
+// Эндпоинты api-sports по лиге и сезону; ключи архива ответов по сезонам.
+const (
+	endpointFixtures       = "fixtures"
+	endpointStandings      = "standings"
+	endpointTeams          = "teams"
+	endpointPlayerProfiles = "players"
+	endpointLeagues        = "leagues"
+)
+
+// PayloadArchive хранит исходные ответы API. Реализация живет рядом с хранилищем
+// (db.PayloadArchive), так что пакет api от БД не зависит.
+type PayloadArchive interface {
+	SaveFixturePayload(ctx context.Context, endpoint string, fixtureID int, body []byte) error
+	// FixturePayload возвращает nil без ошибки, если ответа в архиве нет.
+	FixturePayload(ctx context.Context, endpoint string, fixtureID int) ([]byte, error)
+
+	SaveSeasonPayload(ctx context.Context, endpoint string, leagueID int, season string, body []byte) error
+	// SeasonPayload возвращает nil без ошибки, если ответа в архиве нет.
+	SeasonPayload(ctx context.Context, endpoint string, leagueID int, season string) ([]byte, error)
+}
+
+var archive PayloadArchive
+
+// EnableArchive включает сохранение исходных ответов API в archive.
+func EnableArchive(a PayloadArchive) {
+	archive = a
+}
+
+func archivePayload(ctx context.Context, endpoint string, fixtureID int, body []byte) {
+	if archive == nil {
+		return
+	}
+	if err := archive.SaveFixturePayload(ctx, endpoint, fixtureID, body); err != nil {
+		fmt.Printf("Ошибка архивации ответа: %v\n", err)
+	}
+}
+
+func archiveSeasonPayload(ctx context.Context, endpoint string, leagueID int, season string, body []byte) {
+	if archive == nil {
+		return
+	}
+	if err := archive.SaveSeasonPayload(ctx, endpoint, leagueID, season, body); err != nil {
+		fmt.Printf("Ошибка архивации ответа: %v\n", err)
+	}
+}
+
+// ArchiveProvider отдает ранее сохраненные ответы из архива без обращения к API.
+// Используется для повторного разбора матчей после исправлений парсера.
+type ArchiveProvider struct {
+	archive PayloadArchive
+}
+
+func NewArchiveProvider(archive PayloadArchive) *ArchiveProvider {
+	return &ArchiveProvider{archive: archive}
+}
+
+func (p *ArchiveProvider) FetchSeasonMatches(ctx context.Context, leagueID int, season string) ([]models.Match, error) {
+	body, err := p.loadSeason(ctx, endpointFixtures, leagueID, season)
+	if err != nil {
+		return nil, err
+	}
+	return decodeSeasonMatches(body)
+}
+
+func (p *ArchiveProvider) FetchStatistics(ctx context.Context, fixtureID int) ([]TeamStatistics, error) {
+	body, err := p.load(ctx, endpointStatistics, fixtureID)
+	if err != nil {
+		return nil, err
+	}
+	return decodeStatistics(body)
+}
+
+func (p *ArchiveProvider) FetchLineups(ctx context.Context, fixtureID int) (LineupResponse, error) {
+	body, err := p.load(ctx, endpointLineups, fixtureID)
+	if err != nil {
+		return LineupResponse{}, err
+	}
+	return decodeLineups(body)
+}
+
+func (p *ArchiveProvider) FetchPlayers(ctx context.Context, fixtureID int) (PlayersResponse, error) {
+	body, err := p.load(ctx, endpointPlayers, fixtureID)
+	if err != nil {
+		return PlayersResponse{}, err
+	}
+	return decodePlayers(body)
+}
+
+func (p *ArchiveProvider) FetchEvents(ctx context.Context, fixtureID int) ([]models.MatchEvent, error) {
+	body, err := p.load(ctx, endpointEvents, fixtureID)
+	if err != nil {
+		return nil, err
+	}
+	return decodeEvents(fixtureID, body)
+}
+
+func (p *ArchiveProvider) load(ctx context.Context, endpoint string, fixtureID int) (io.Reader, error) {
+	body, err := p.archive.FixturePayload(ctx, endpoint, fixtureID)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return nil, fmt.Errorf("%w: нет архива %s для матча ID=%d", ErrNotFound, endpoint, fixtureID)
+	}
+	return bytes.NewReader(body), nil
+}
+
+func (p *ArchiveProvider) loadSeason(ctx context.Context, endpoint string, leagueID int, season string) (io.Reader, error) {
+	body, err := p.archive.SeasonPayload(ctx, endpoint, leagueID, season)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return nil, fmt.Errorf("%w: нет архива %s для лиги %d, сезон %s", ErrNotFound, endpoint, leagueID, season)
+	}
+	return bytes.NewReader(body), nil
+}
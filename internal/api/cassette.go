@@ -0,0 +1,120 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	CassetteRecord = "record"
+	CassetteReplay = "replay"
+)
+
+// errCassetteMiss — в кассете нет записи для запроса. Не повторяется и не считается
+// отсутствием данных: неполная кассета должна останавливать загрузку матча.
+var errCassetteMiss = errors.New("запись не найдена в кассете")
+
+// cassetteEntry — записанный ответ API. Заголовки запроса (ключ API) не сохраняются.
+type cassetteEntry struct {
+	URL    string      `json:"url"`
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   string      `json:"body"`
+}
+
+// cassetteTransport в режиме record пишет каждый ответ в каталог Dir,
+// в режиме replay отдает записанные ответы без обращения к сети.
+type cassetteTransport struct {
+	Mode string
+	Dir  string
+	Next http.RoundTripper
+}
+
+// UseCassette переключает HTTP-слой api-sports на запись или воспроизведение кассеты.
+func UseCassette(mode, dir string) error {
+	if mode != CassetteRecord && mode != CassetteReplay {
+		return fmt.Errorf("неизвестный режим кассеты: %s", mode)
+	}
+	if dir == "" {
+		return fmt.Errorf("не указан каталог кассеты")
+	}
+	if mode == CassetteRecord {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("ошибка создания каталога кассеты: %v", err)
+		}
+	}
+	httpClient.Transport = &cassetteTransport{Mode: mode, Dir: dir, Next: http.DefaultTransport}
+	return nil
+}
+
+func isReplaying() bool {
+	t, ok := httpClient.Transport.(*cassetteTransport)
+	return ok && t.Mode == CassetteReplay
+}
+
+func (t *cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := filepath.Join(t.Dir, cassetteFileName(req))
+	if t.Mode == CassetteReplay {
+		return t.replay(req, path)
+	}
+
+	resp, err := t.Next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	entry := cassetteEntry{URL: req.URL.String(), Status: resp.StatusCode, Header: resp.Header, Body: string(body)}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сериализации записи кассеты: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return nil, fmt.Errorf("ошибка записи кассеты %s: %v", path, err)
+	}
+	return resp, nil
+}
+
+func (t *cassetteTransport) replay(req *http.Request, path string) (*http.Response, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", errCassetteMiss, req.URL.String())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения кассеты %s: %v", path, err)
+	}
+
+	var entry cassetteEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("ошибка парсинга кассеты %s: %v", path, err)
+	}
+	return &http.Response{
+		Status:        http.StatusText(entry.Status),
+		StatusCode:    entry.Status,
+		Header:        entry.Header,
+		Body:          io.NopCloser(strings.NewReader(entry.Body)),
+		ContentLength: int64(len(entry.Body)),
+		Request:       req,
+	}, nil
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// cassetteFileName строит имя файла из пути и параметров: /fixtures/lineups?fixture=1 → fixtures_lineups_fixture_1.json.
+func cassetteFileName(req *http.Request) string {
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(req.URL.Path+"_"+req.URL.RawQuery, "_"), "_")
+	return name + ".json"
+}
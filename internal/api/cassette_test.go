@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// useTestKey направляет запросы на baseURL одним ключом и восстанавливает
+// HTTP-слой после теста.
+func useTestKey(t *testing.T, baseURL string) {
+	t.Helper()
+	pool, err := NewKeyPool([]*APIKey{{Name: "test", Key: "secret", Scheme: SchemeAPISports, BaseURL: baseURL}})
+	if err != nil {
+		t.Fatalf("NewKeyPool: %v", err)
+	}
+	prevPool, prevTransport := keyPool, httpClient.Transport
+	keyPool = pool
+	t.Cleanup(func() {
+		keyPool, httpClient.Transport = prevPool, prevTransport
+	})
+}
+
+func TestCassetteRecordReplay(t *testing.T) {
+	payload, err := os.ReadFile(filepath.Join("testdata", "statistics_1035037.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/fixtures/statistics" || r.URL.Query().Get("fixture") != "1035037" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set(headerDayRemaining, "100")
+		w.Write(payload)
+	}))
+	useTestKey(t, server.URL)
+
+	dir := t.TempDir()
+	provider := NewAPISportsProvider()
+	ctx := context.Background()
+
+	if err := UseCassette(CassetteRecord, dir); err != nil {
+		t.Fatalf("UseCassette(record): %v", err)
+	}
+	recorded, err := provider.FetchStatistics(ctx, 1035037)
+	if err != nil {
+		t.Fatalf("запись: %v", err)
+	}
+	server.Close()
+
+	if err := UseCassette(CassetteReplay, dir); err != nil {
+		t.Fatalf("UseCassette(replay): %v", err)
+	}
+	tests := []struct {
+		name      string
+		fixtureID int
+		wantErr   error
+	}{
+		{"записанный ответ", 1035037, nil},
+		{"нет записи", 1035038, errCassetteMiss},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replayed, err := provider.FetchStatistics(ctx, tt.fixtureID)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ошибка %v, ожидалась %v", err, tt.wantErr)
+				}
+				if errors.Is(err, ErrNotFound) || isRetryable(err, 0) {
+					t.Errorf("пропуск записи не должен выглядеть как пустой ответ или повторяться: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("воспроизведение: %v", err)
+			}
+			if !reflect.DeepEqual(replayed, recorded) {
+				t.Errorf("воспроизведенный ответ отличается от записанного")
+			}
+		})
+	}
+	if requests != 1 {
+		t.Errorf("запросов к серверу %d, ожидался 1: воспроизведение не должно ходить в сеть", requests)
+	}
+}
+
+func TestCassetteFileName(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://v3.football.api-sports.io/fixtures/lineups?fixture=1", "fixtures_lineups_fixture_1.json"},
+		{"https://v3.football.api-sports.io/fixtures?league=39&season=2023", "fixtures_league_39_season_2023.json"},
+		{"https://v3.football.api-sports.io/leagues?id=39", "leagues_id_39.json"},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest("GET", tt.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := cassetteFileName(req); got != tt.want {
+			t.Errorf("cassetteFileName(%s) = %s, ожидалось %s", tt.url, got, tt.want)
+		}
+	}
+}
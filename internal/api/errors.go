@@ -0,0 +1,60 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrRateLimited = errors.New("превышен лимит запросов API")
+	ErrNotFound    = errors.New("данные не найдены")
+	ErrUpstream    = errors.New("ошибка API")
+	ErrDecode      = errors.New("ошибка декодирования ответа API")
+)
+
+const (
+	maxAttempts = 5
+	baseBackoff = time.Second
+	maxBackoff  = 30 * time.Second
+)
+
+// statusError сопоставляет HTTP-статус одной из типизированных ошибок.
+func statusError(status int, body string) error {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return fmt.Errorf("%w: статус %d: %s", ErrRateLimited, status, body)
+	case status == http.StatusNotFound:
+		return fmt.Errorf("%w: статус %d: %s", ErrNotFound, status, body)
+	default:
+		return fmt.Errorf("%w: статус %d: %s", ErrUpstream, status, body)
+	}
+}
+
+// errEmptyResponse — api-sports сообщает об отсутствии данных не статусом 404,
+// а статусом 200 с пустым "response".
+func errEmptyResponse() error {
+	return fmt.Errorf("%w: пустой ответ API", ErrNotFound)
+}
+
+// isRetryable — повторяем лимиты, 5xx и сетевые ошибки; остальные 4xx и ошибки парсинга — нет.
+func isRetryable(err error, status int) bool {
+	if errors.Is(err, ErrRateLimited) || errors.Is(err, errKeyRejected) {
+		return true
+	}
+	if errors.Is(err, ErrUpstream) {
+		return status == 0 || status >= 500
+	}
+	return false
+}
+
+// backoff — экспоненциальная задержка с джиттером для попытки attempt (с нуля).
+func backoff(attempt int) time.Duration {
+	d := baseBackoff << attempt
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
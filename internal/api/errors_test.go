@@ -0,0 +1,51 @@
+package api
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		want   bool
+	}{
+		{"лимит", fmt.Errorf("%w: статус 429", ErrRateLimited), 429, true},
+		{"отклоненный ключ", fmt.Errorf("%w: статус 403", errKeyRejected), 403, true},
+		{"сетевая ошибка", fmt.Errorf("%w: timeout", ErrUpstream), 0, true},
+		{"5xx", statusError(502, "bad gateway"), 502, true},
+		{"4xx", statusError(400, "bad request"), 400, false},
+		{"404", statusError(404, ""), 404, false},
+		{"ошибка декодирования", fmt.Errorf("%w: unexpected EOF", ErrDecode), 200, false},
+		{"пустой ответ", errEmptyResponse(), 200, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.err, tt.status); got != tt.want {
+				t.Errorf("isRetryable(%v, %d) = %t, ожидалось %t", tt.err, tt.status, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{0, baseBackoff / 2, baseBackoff},
+		{1, baseBackoff, 2 * baseBackoff},
+		{3, 4 * baseBackoff, 8 * baseBackoff},
+		{10, maxBackoff / 2, maxBackoff},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			if d := backoff(tt.attempt); d < tt.min || d > tt.max {
+				t.Fatalf("backoff(%d) = %s, ожидалось от %s до %s", tt.attempt, d, tt.min, tt.max)
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"football-data-miner/internal/models"
+	"io"
+)
+
+type EventsResponse struct {
+	Response []struct {
+		Time struct {
+			Elapsed int  `json:"elapsed"`
+			Extra   *int `json:"extra"`
+		} `json:"time"`
+		Team struct {
+			ID int `json:"id"`
+		} `json:"team"`
+		Player struct {
+			ID *int `json:"id"`
+		} `json:"player"`
+		Assist struct {
+			ID *int `json:"id"`
+		} `json:"assist"`
+		Type     string  `json:"type"`
+		Detail   string  `json:"detail"`
+		Comments *string `json:"comments"`
+	} `json:"response"`
+}
+
+func (p *APISportsProvider) FetchEvents(ctx context.Context, fixtureID int) ([]models.MatchEvent, error) {
+	body, err := p.fetchFixture(ctx, endpointEvents, fixtureID)
+	if err != nil {
+		return nil, err
+	}
+	return decodeEvents(fixtureID, body)
+}
+
+func (p *FixtureProvider) FetchEvents(ctx context.Context, fixtureID int) ([]models.MatchEvent, error) {
+	file, err := p.open(fmt.Sprintf("events_%d.json", fixtureID))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return decodeEvents(fixtureID, file)
+}
+
+func decodeEvents(fixtureID int, body io.Reader) ([]models.MatchEvent, error) {
+	var eventsResp EventsResponse
+	if err := json.NewDecoder(body).Decode(&eventsResp); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecode, err)
+	}
+	if len(eventsResp.Response) == 0 {
+		return nil, errEmptyResponse()
+	}
+
+	var events []models.MatchEvent
+	for i, e := range eventsResp.Response {
+		event := models.MatchEvent{
+			MatchID:  fixtureID,
+			Sequence: i + 1,
+			TeamID:   e.Team.ID,
+			PlayerID: e.Player.ID,
+			AssistID: e.Assist.ID,
+			Elapsed:  e.Time.Elapsed,
+			Extra:    e.Time.Extra,
+			Type:     e.Type,
+			Detail:   e.Detail,
+		}
+		event.Comments = derefString(e.Comments)
+		events = append(events, event)
+	}
+	return events, nil
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"football-data-miner/internal/models"
+	"os"
+	"path/filepath"
+)
+
+// FixtureProvider читает сохраненные ответы api-sports из каталога:
+//
+//	fixtures_<league>_<season>.json
+//	statistics_<fixture>.json
+//	lineups_<fixture>.json
+//	players_<fixture>.json
+//	events_<fixture>.json
+//	injuries_<fixture>.json
+//	odds_<fixture>.json
+//	standings_<league>_<season>.json
+//	teams_<league>_<season>.json
+//	players_<league>_<season>_<page>.json
+//	leagues_<league>.json
+//
+// Формат файлов совпадает с телом ответа API, поэтому декодирование общее.
+// Отсутствующий файл возвращает ErrNotFound, как пустой ответ API.
+type FixtureProvider struct {
+	Dir string
+}
+
+func NewFixtureProvider(dir string) *FixtureProvider {
+	return &FixtureProvider{Dir: dir}
+}
+
+func (p *FixtureProvider) FetchSeasonMatches(ctx context.Context, leagueID int, season string) ([]models.Match, error) {
+	file, err := p.open(fmt.Sprintf("fixtures_%d_%s.json", leagueID, season))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return decodeSeasonMatches(file)
+}
+
+func (p *FixtureProvider) FetchStatistics(ctx context.Context, fixtureID int) ([]TeamStatistics, error) {
+	file, err := p.open(fmt.Sprintf("statistics_%d.json", fixtureID))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return decodeStatistics(file)
+}
+
+func (p *FixtureProvider) FetchLineups(ctx context.Context, fixtureID int) (LineupResponse, error) {
+	file, err := p.open(fmt.Sprintf("lineups_%d.json", fixtureID))
+	if err != nil {
+		return LineupResponse{}, err
+	}
+	defer file.Close()
+	return decodeLineups(file)
+}
+
+func (p *FixtureProvider) FetchPlayers(ctx context.Context, fixtureID int) (PlayersResponse, error) {
+	file, err := p.open(fmt.Sprintf("players_%d.json", fixtureID))
+	if err != nil {
+		return PlayersResponse{}, err
+	}
+	defer file.Close()
+	return decodePlayers(file)
+}
+
+func (p *FixtureProvider) open(name string) (*os.File, error) {
+	file, err := os.Open(filepath.Join(p.Dir, name))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: фикстура %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения фикстуры %s: %v", name, err)
+	}
+	return file, nil
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFixtureProviderSeasonMatches(t *testing.T) {
+	provider := NewFixtureProvider("testdata")
+	matches, err := provider.FetchSeasonMatches(context.Background(), 39, "2023")
+	if err != nil {
+		t.Fatalf("FetchSeasonMatches: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("матчей %d, ожидалось 2", len(matches))
+	}
+
+	finished, pending := matches[0], matches[1]
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"ID", finished.ID, 1035037},
+		{"LeagueID", finished.LeagueID, 39},
+		{"Round", finished.Round, "Regular Season - 1"},
+		{"Status", finished.Status, "FT"},
+		{"HomeTeamID", finished.HomeTeamID, 44},
+		{"AwayTeamName", finished.AwayTeamName, "Manchester City"},
+		{"AwayScore", *finished.AwayScore, 3},
+		{"HomeHalftime", *finished.HomeHalftime, 0},
+		{"Referee", finished.Referee, "C. Kavanagh"},
+		{"VenueID", *finished.VenueID, 512},
+		{"IsFinished", finished.IsFinished(), true},
+		{"pending IsFinished", pending.IsFinished(), false},
+		{"pending HomeScore", pending.HomeScore == nil, true},
+		{"pending Referee", pending.Referee, ""},
+		{"pending VenueID", pending.VenueID == nil, true},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, ожидалось %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestFixtureProviderNotFound(t *testing.T) {
+	provider := NewFixtureProvider("testdata")
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		fetch func() error
+	}{
+		{"пустой response", func() error {
+			_, err := provider.FetchStatistics(ctx, 1035038)
+			return err
+		}},
+		{"нет файла статистики", func() error {
+			_, err := provider.FetchStatistics(ctx, 1)
+			return err
+		}},
+		{"нет файла составов", func() error {
+			_, err := provider.FetchLineups(ctx, 1035038)
+			return err
+		}},
+		{"нет сезона", func() error {
+			_, err := provider.FetchSeasonMatches(ctx, 39, "1999")
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fetch(); !errors.Is(err, ErrNotFound) {
+				t.Errorf("ошибка %v, ожидалась ErrNotFound", err)
+			}
+		})
+	}
+}
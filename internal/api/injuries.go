@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"football-data-miner/internal/models"
+	"io"
+)
+
+const endpointInjuries = "injuries"
+
+type InjuriesResponse struct {
+	Response []struct {
+		Player struct {
+			ID     int     `json:"id"`
+			Name   string  `json:"name"`
+			Type   string  `json:"type"`
+			Reason *string `json:"reason"`
+		} `json:"player"`
+		Team struct {
+			ID int `json:"id"`
+		} `json:"team"`
+	} `json:"response"`
+}
+
+func (p *APISportsProvider) FetchInjuries(ctx context.Context, fixtureID int) ([]models.PlayerAbsence, error) {
+	body, err := p.fetchFixture(ctx, endpointInjuries, fixtureID)
+	if err != nil {
+		return nil, err
+	}
+	return decodeInjuries(fixtureID, body)
+}
+
+func (p *FixtureProvider) FetchInjuries(ctx context.Context, fixtureID int) ([]models.PlayerAbsence, error) {
+	file, err := p.open(fmt.Sprintf("injuries_%d.json", fixtureID))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return decodeInjuries(fixtureID, file)
+}
+
+func (p *ArchiveProvider) FetchInjuries(ctx context.Context, fixtureID int) ([]models.PlayerAbsence, error) {
+	body, err := p.load(ctx, endpointInjuries, fixtureID)
+	if err != nil {
+		return nil, err
+	}
+	return decodeInjuries(fixtureID, body)
+}
+
+func decodeInjuries(fixtureID int, body io.Reader) ([]models.PlayerAbsence, error) {
+	var injuriesResp InjuriesResponse
+	if err := json.NewDecoder(body).Decode(&injuriesResp); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecode, err)
+	}
+	if len(injuriesResp.Response) == 0 {
+		return nil, errEmptyResponse()
+	}
+
+	var absences []models.PlayerAbsence
+	for _, r := range injuriesResp.Response {
+		absences = append(absences, models.PlayerAbsence{
+			MatchID:    fixtureID,
+			PlayerID:   r.Player.ID,
+			PlayerName: r.Player.Name,
+			TeamID:     r.Team.ID,
+			Type:       r.Player.Type,
+			Reason:     derefString(r.Player.Reason),
+		})
+	}
+	return absences, nil
+}
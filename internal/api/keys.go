@@ -0,0 +1,160 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Схемы авторизации: через RapidAPI или напрямую в api-sports.
+const (
+	SchemeRapidAPI  = "rapidapi"
+	SchemeAPISports = "apisports"
+)
+
+var defaultBaseURLs = map[string]string{
+	SchemeRapidAPI:  "https://api-football-v1.p.rapidapi.com/v3",
+	SchemeAPISports: "https://v3.football.api-sports.io",
+}
+
+var (
+	errKeyRejected = errors.New("ключ API отклонен")
+	errDailyQuota  = errors.New("суточная квота ключа исчерпана")
+)
+
+// APIKey — одна подписка. Квота отслеживается собственным лимитером по заголовкам ответов.
+type APIKey struct {
+	Name     string `json:"name"`
+	Key      string `json:"key"`
+	Scheme   string `json:"scheme"`
+	BaseURL  string `json:"base_url"`
+	Disabled bool   `json:"disabled"`
+
+	limiter *RateLimiter
+}
+
+// KeyPool выбирает для каждого запроса включенный ключ с наибольшим запасом суточной квоты.
+type KeyPool struct {
+	mu   sync.Mutex
+	keys []*APIKey
+}
+
+func NewKeyPool(keys []*APIKey) (*KeyPool, error) {
+	for _, k := range keys {
+		if k.Scheme == "" {
+			k.Scheme = SchemeRapidAPI
+		}
+		if _, ok := defaultBaseURLs[k.Scheme]; !ok {
+			return nil, fmt.Errorf("неизвестная схема ключа %s: %s", k.Name, k.Scheme)
+		}
+		if k.BaseURL == "" {
+			k.BaseURL = defaultBaseURLs[k.Scheme]
+		}
+		k.BaseURL = strings.TrimSuffix(k.BaseURL, "/")
+		k.limiter = NewRateLimiter(100 * time.Millisecond)
+	}
+	return &KeyPool{keys: keys}, nil
+}
+
+// LoadKeyPool читает ключи из JSON-файла:
+//
+//	[{"name": "main", "key": "...", "scheme": "apisports"},
+//	 {"name": "rapid", "key": "...", "scheme": "rapidapi", "disabled": true}]
+func LoadKeyPool(path string) (*KeyPool, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения файла ключей: %v", err)
+	}
+	var keys []*APIKey
+	if err := json.Unmarshal(file, &keys); err != nil {
+		return nil, fmt.Errorf("ошибка парсинга файла ключей: %v", err)
+	}
+	return NewKeyPool(keys)
+}
+
+// keyPoolFromEnv берет пул из API_KEYS_FILE, иначе — единственный ключ API_KEY
+// с адресом API_BASE_URL, как раньше.
+func keyPoolFromEnv() (*KeyPool, error) {
+	if path := os.Getenv("API_KEYS_FILE"); path != "" {
+		return LoadKeyPool(path)
+	}
+	apiKey := os.Getenv("API_KEY")
+	if apiKey == "" && !isReplaying() {
+		return nil, fmt.Errorf("API_KEY не установлен")
+	}
+	return NewKeyPool([]*APIKey{{
+		Name:    "default",
+		Key:     apiKey,
+		Scheme:  SchemeRapidAPI,
+		BaseURL: os.Getenv("API_BASE_URL"),
+	}})
+}
+
+var keyPool *KeyPool
+
+// UseKeyPool задает пул ключей для всех запросов к api-sports.
+func UseKeyPool(pool *KeyPool) {
+	keyPool = pool
+}
+
+// acquire возвращает включенный ключ с наибольшим запасом. Если все исчерпаны,
+// возвращается ключ с ближайшим сбросом — его лимитер дождется нового окна.
+func (p *KeyPool) acquire() (*APIKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var best *APIKey
+	bestHeadroom := math.MinInt
+	for _, k := range p.keys {
+		if k.Disabled {
+			continue
+		}
+		if headroom := k.limiter.Headroom(); headroom > bestHeadroom {
+			best, bestHeadroom = k, headroom
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("нет доступных ключей API")
+	}
+	if bestHeadroom > 0 {
+		return best, nil
+	}
+
+	for _, k := range p.keys {
+		if !k.Disabled && k.limiter.ResetAt().Before(best.limiter.ResetAt()) {
+			best = k
+		}
+	}
+	return best, nil
+}
+
+func (p *KeyPool) disable(k *APIKey, reason string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !k.Disabled {
+		k.Disabled = true
+		fmt.Printf("Ключ API %s отключен: %s\n", k.Name, reason)
+	}
+}
+
+func (k *APIKey) setHeaders(req *http.Request) {
+	switch k.Scheme {
+	case SchemeAPISports:
+		req.Header.Set("x-apisports-key", k.Key)
+	default:
+		host := "v3.football.api-sports.io"
+		if u, err := url.Parse(k.BaseURL); err == nil && u.Host != "" {
+			host = u.Host
+		}
+		req.Header.Set("X-RapidAPI-Key", k.Key)
+		req.Header.Set("X-RapidAPI-Host", host)
+	}
+}
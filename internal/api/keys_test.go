@@ -0,0 +1,50 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKeyPoolAcquire(t *testing.T) {
+	now := time.Now()
+	// quota: -1 — квота неизвестна, иначе остаток суточной квоты со сбросом через reset
+	type keyState struct {
+		quota    int
+		reset    time.Duration
+		disabled bool
+	}
+	tests := []struct {
+		name string
+		keys []keyState
+		want int
+	}{
+		{"наибольший запас", []keyState{{quota: 20}, {quota: 80}, {quota: 50}}, 1},
+		{"неизвестная квота раньше известной", []keyState{{quota: 80}, {quota: -1}}, 1},
+		{"отключенный пропускается", []keyState{{quota: 80, disabled: true}, {quota: 10}}, 1},
+		{"все исчерпаны — ближайший сброс", []keyState{
+			{quota: 0, reset: 3 * time.Hour},
+			{quota: 2, reset: time.Hour},
+			{quota: 1, reset: 2 * time.Hour},
+		}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keys []*APIKey
+			for _, state := range tt.keys {
+				limiter := NewRateLimiter(0)
+				limiter.dayRemaining = state.quota
+				limiter.dayReset = now.Add(state.reset + time.Hour)
+				keys = append(keys, &APIKey{Name: "key", Disabled: state.disabled, limiter: limiter})
+			}
+			pool := &KeyPool{keys: keys}
+
+			got, err := pool.acquire()
+			if err != nil {
+				t.Fatalf("acquire: %v", err)
+			}
+			if got != keys[tt.want] {
+				t.Errorf("выбран не тот ключ, ожидался ключ %d", tt.want)
+			}
+		})
+	}
+}
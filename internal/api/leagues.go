@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"football-data-miner/internal/models"
+	"io"
+	"strconv"
+)
+
+type LeaguesResponse struct {
+	Response []struct {
+		League struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		} `json:"league"`
+		Country struct {
+			Name string `json:"name"`
+		} `json:"country"`
+		Seasons []struct {
+			Year     int    `json:"year"`
+			Start    string `json:"start"`
+			End      string `json:"end"`
+			Coverage struct {
+				Fixtures struct {
+					Events             bool `json:"events"`
+					Lineups            bool `json:"lineups"`
+					StatisticsFixtures bool `json:"statistics_fixtures"`
+					StatisticsPlayers  bool `json:"statistics_players"`
+				} `json:"fixtures"`
+				Standings bool `json:"standings"`
+				Players   bool `json:"players"`
+				Injuries  bool `json:"injuries"`
+				Odds      bool `json:"odds"`
+			} `json:"coverage"`
+		} `json:"seasons"`
+	} `json:"response"`
+}
+
+// FetchLeagueSeasons возвращает все сезоны лиги, известные api-sports, с покрытием данных.
+func (p *APISportsProvider) FetchLeagueSeasons(ctx context.Context, leagueID int) ([]models.LeagueSeason, error) {
+	path := fmt.Sprintf("%s?id=%d", endpointLeagues, leagueID)
+	body, err := p.fetchSeason(ctx, endpointLeagues, path, leagueID, "")
+	if err != nil {
+		return nil, err
+	}
+	return decodeLeagueSeasons(body)
+}
+
+func (p *FixtureProvider) FetchLeagueSeasons(ctx context.Context, leagueID int) ([]models.LeagueSeason, error) {
+	file, err := p.open(fmt.Sprintf("leagues_%d.json", leagueID))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return decodeLeagueSeasons(file)
+}
+
+func (p *ArchiveProvider) FetchLeagueSeasons(ctx context.Context, leagueID int) ([]models.LeagueSeason, error) {
+	body, err := p.loadSeason(ctx, endpointLeagues, leagueID, "")
+	if err != nil {
+		return nil, err
+	}
+	return decodeLeagueSeasons(body)
+}
+
+func decodeLeagueSeasons(body io.Reader) ([]models.LeagueSeason, error) {
+	var leaguesResp LeaguesResponse
+	if err := json.NewDecoder(body).Decode(&leaguesResp); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecode, err)
+	}
+	if len(leaguesResp.Response) == 0 {
+		return nil, errEmptyResponse()
+	}
+
+	var seasons []models.LeagueSeason
+	for _, r := range leaguesResp.Response {
+		for _, s := range r.Seasons {
+			coverage := s.Coverage
+			seasons = append(seasons, models.LeagueSeason{
+				LeagueID:   r.League.ID,
+				Season:     strconv.Itoa(s.Year),
+				LeagueName: r.League.Name,
+				Country:    r.Country.Name,
+				StartDate:  optionalString(s.Start),
+				EndDate:    optionalString(s.End),
+				Coverage: models.Coverage{
+					Events:     coverage.Fixtures.Events,
+					Lineups:    coverage.Fixtures.Lineups,
+					Statistics: coverage.Fixtures.StatisticsFixtures,
+					Players:    coverage.Fixtures.StatisticsPlayers,
+					Standings:  coverage.Standings,
+					Injuries:   coverage.Injuries,
+					Odds:       coverage.Odds,
+				},
+			})
+		}
+	}
+	return seasons, nil
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
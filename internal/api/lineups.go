@@ -1,8 +1,6 @@
 package api
 
 import (
-	"fmt"
-	"football-data-miner/internal/db"
 	"football-data-miner/internal/models"
 	"strconv"
 )
@@ -17,22 +15,19 @@ type LineupResponse struct {
 		Team      struct {
 			ID int `json:"id"`
 		} `json:"team"`
-		StartXI []struct {
-			Player struct {
-				ID   int    `json:"id"`
-				Name string `json:"name"`
-				Pos  string `json:"pos"`
-			} `json:"player"`
-		} `json:"startXI"`
-		Substitutes []struct {
-			Player struct {
-				ID   int    `json:"id"`
-				Name string `json:"name"`
-				Pos  string `json:"pos"`
-			} `json:"player"`
-		} `json:"substitutes"`
+		StartXI     []LineupPlayer `json:"startXI"`
+		Substitutes []LineupPlayer `json:"substitutes"`
 	} `json:"response"`
 }
+type LineupPlayer struct {
+	Player struct {
+		ID     int    `json:"id"`
+		Name   string `json:"name"`
+		Number int    `json:"number"`
+		Pos    string `json:"pos"`
+	} `json:"player"`
+}
+
 type PlayersResponse struct {
 	Response []struct {
 		Team struct {
@@ -54,6 +49,7 @@ type PlayerStatistics struct {
 	Dribbles struct {
 		Attempts int `json:"attempts"`
 		Success  int `json:"success"`
+		Past     int `json:"past"`
 	} `json:"dribbles"`
 	Duels struct {
 		Won   int `json:"won"`
@@ -66,6 +62,7 @@ type PlayerStatistics struct {
 	Games struct {
 		Captain    bool   `json:"captain"`
 		Minutes    int    `json:"minutes"`
+		Number     int    `json:"number"`
 		Rating     string `json:"rating"`
 		Substitute bool   `json:"substitute"`
 	} `json:"games"`
@@ -75,10 +72,19 @@ type PlayerStatistics struct {
 		Saves    int `json:"saves"`
 		Total    int `json:"total"`
 	} `json:"goals"`
-	Passes struct {
+	Offsides int `json:"offsides"`
+	Passes   struct {
 		Accuracy string `json:"accuracy"`
+		Key      int    `json:"key"`
 		Total    int    `json:"total"`
 	} `json:"passes"`
+	Penalty struct {
+		Won       int `json:"won"`
+		Committed int `json:"commited"` // Так в API
+		Scored    int `json:"scored"`
+		Missed    int `json:"missed"`
+		Saved     int `json:"saved"`
+	} `json:"penalty"`
 	Shots struct {
 		On    int `json:"on"`
 		Total int `json:"total"`
@@ -90,20 +96,20 @@ type PlayerStatistics struct {
 	} `json:"tackles"`
 }
 
+// MergeLineupAndPlayers собирает строки составов из /fixtures/lineups и /fixtures/players
+// и заполняет тренеров и схемы матча. В БД ничего не пишет: тренеры и игроки сохраняются
+// в транзакции матча в db.SaveMatchDetails.
 func MergeLineupAndPlayers(lineupResp LineupResponse, playersResp PlayersResponse, match *models.Match) []models.Lineup {
 	var lineups []models.Lineup
 
 	for _, teamLineup := range lineupResp.Response {
-		err := db.SaveCoachIfNotExists(teamLineup.Coach.ID, teamLineup.Coach.Name)
-		if err != nil {
-			fmt.Printf("Ошибка сохранения тренера %d: %v\n", teamLineup.Coach.ID, err)
-			continue
-		}
 		if teamLineup.Team.ID == match.HomeTeamID {
 			match.HomeCoachID = teamLineup.Coach.ID
+			match.HomeCoachName = teamLineup.Coach.Name
 			match.HomeFormation = teamLineup.Formation
 		} else {
 			match.AwayCoachID = teamLineup.Coach.ID
+			match.AwayCoachName = teamLineup.Coach.Name
 			match.AwayFormation = teamLineup.Formation
 		}
 		processPlayers(match.ID, teamLineup.Team.ID, teamLineup.StartXI, playersResp, &lineups, false)
@@ -113,25 +119,19 @@ func MergeLineupAndPlayers(lineupResp LineupResponse, playersResp PlayersRespons
 	return lineups
 }
 
-func processPlayers(matchID, teamID int, players []struct {
-	Player struct {
-		ID   int    `json:"id"`
-		Name string `json:"name"`
-		Pos  string `json:"pos"`
-	} `json:"player"`
-}, playersResp PlayersResponse, lineups *[]models.Lineup, isSubstitute bool) {
+func processPlayers(matchID, teamID int, players []LineupPlayer, playersResp PlayersResponse, lineups *[]models.Lineup, isSubstitute bool) {
 	for _, player := range players {
-		err := db.SavePlayerIfNotExists(player.Player.ID, player.Player.Name)
-		if err != nil {
-			fmt.Printf("Ошибка сохранения игрока %d: %v\n", player.Player.ID, err)
-			continue
-		}
-
 		stats := findPlayerStats(teamID, player.Player.ID, playersResp)
+		number := player.Player.Number
+		if number == 0 {
+			number = stats.Games.Number
+		}
 		lineup := models.Lineup{
 			MatchID:              matchID,
 			PlayerID:             player.Player.ID,
+			PlayerName:           player.Player.Name,
 			TeamID:               teamID,
+			Number:               number,
 			Position:             player.Player.Pos,
 			IsSubstitute:         isSubstitute,
 			YellowCards:          stats.Cards.Yellow,
@@ -142,8 +142,11 @@ func processPlayers(matchID, teamID int, players []struct {
 			FoulsDrawn:           safeInt(stats.Fouls.Drawn),
 			DribblesAttempts:     safeInt(stats.Dribbles.Attempts),
 			DribblesSuccess:      safeInt(stats.Dribbles.Success),
+			DribblesPast:         safeInt(stats.Dribbles.Past),
 			DuelsWon:             safeInt(stats.Duels.Won),
+			DuelsTotal:           safeInt(stats.Duels.Total),
 			PassesTotal:          safeInt(stats.Passes.Total),
+			KeyPasses:            safeInt(stats.Passes.Key),
 			PassesAccuracy:       parsePercentage(stats.Passes.Accuracy),
 			TacklesTotal:         safeInt(stats.Tackles.Total),
 			TacklesBlocks:        safeInt(stats.Tackles.Blocks),
@@ -155,6 +158,12 @@ func processPlayers(matchID, teamID int, players []struct {
 			Minutes:              safeInt(stats.Games.Minutes),
 			Captain:              stats.Games.Captain,
 			Rating:               parseRating(stats.Games.Rating),
+			Offsides:             safeInt(stats.Offsides),
+			PenaltyWon:           safeInt(stats.Penalty.Won),
+			PenaltyCommitted:     safeInt(stats.Penalty.Committed),
+			PenaltyScored:        safeInt(stats.Penalty.Scored),
+			PenaltyMissed:        safeInt(stats.Penalty.Missed),
+			PenaltySaved:         safeInt(stats.Penalty.Saved),
 		}
 		*lineups = append(*lineups, lineup)
 	}
@@ -163,7 +172,7 @@ func findPlayerStats(teamID, playerID int, playersResp PlayersResponse) *PlayerS
 	for _, teamResp := range playersResp.Response {
 		if teamResp.Team.ID == teamID {
 			for _, playerData := range teamResp.Players {
-				if playerData.Player.ID == playerID {
+				if playerData.Player.ID == playerID && len(playerData.Statistics) > 0 {
 					return &playerData.Statistics[0]
 				}
 			}
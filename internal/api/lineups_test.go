@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"football-data-miner/internal/models"
+	"testing"
+)
+
+func TestMergeLineupAndPlayers(t *testing.T) {
+	provider := NewFixtureProvider("testdata")
+	ctx := context.Background()
+	lineupResp, err := provider.FetchLineups(ctx, 1035037)
+	if err != nil {
+		t.Fatalf("FetchLineups: %v", err)
+	}
+	playersResp, err := provider.FetchPlayers(ctx, 1035037)
+	if err != nil {
+		t.Fatalf("FetchPlayers: %v", err)
+	}
+
+	match := models.Match{ID: 1035037, HomeTeamID: 44, AwayTeamID: 50}
+	lineups := MergeLineupAndPlayers(lineupResp, playersResp, &match)
+	if len(lineups) != 5 {
+		t.Fatalf("строк составов %d, ожидалось 5", len(lineups))
+	}
+	if match.HomeCoachName != "V. Kompany" || match.HomeFormation != "4-2-3-1" || match.AwayFormation != "4-1-4-1" {
+		t.Errorf("тренеры и схемы заполнены неверно: %+v", match)
+	}
+
+	byPlayer := make(map[int]models.Lineup, len(lineups))
+	for _, lineup := range lineups {
+		byPlayer[lineup.PlayerID] = lineup
+	}
+	tests := []struct {
+		name     string
+		playerID int
+		check    func(models.Lineup) bool
+	}{
+		{"вратарь: пропущенные и сейвы", 162489, func(l models.Lineup) bool {
+			return l.TeamID == 44 && l.GoalsConceded == 3 && l.GoalsSaved == 5 && l.Rating == 6.4
+		}},
+		{"капитан с карточкой", 18961, func(l models.Lineup) bool {
+			return l.Captain && l.YellowCards == 1 && l.DuelsWon == 6 && l.DribblesPast == 2 && l.PassesAccuracy == 41
+		}},
+		{"запасной: номер из /fixtures/players", 284492, func(l models.Lineup) bool {
+			return l.IsSubstitute && l.Number == 17 && l.Minutes == 12 && l.Rating == 0 && l.Offsides == 1
+		}},
+		{"голы гостей", 1100, func(l models.Lineup) bool {
+			return l.TeamID == 50 && l.Goals == 2 && l.ShotsOn == 3 && l.Rating == 8.9
+		}},
+		{"нет статистики игрока", 617, func(l models.Lineup) bool {
+			return l.TeamID == 50 && l.Number == 31 && l.Minutes == 0
+		}},
+	}
+	for _, tt := range tests {
+		lineup, ok := byPlayer[tt.playerID]
+		if !ok {
+			t.Errorf("%s: игрок %d не найден", tt.name, tt.playerID)
+			continue
+		}
+		if !tt.check(lineup) {
+			t.Errorf("%s: %+v", tt.name, lineup)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"football-data-miner/internal/models"
+	"io"
+	"strconv"
+)
+
+const endpointOdds = "odds"
+
+// ID ставок api-sports для сохраняемых рынков.
+var oddsMarkets = map[int]string{
+	1: models.Market1X2,       // Match Winner
+	5: models.MarketOverUnder, // Goals Over/Under
+	8: models.MarketBTTS,      // Both Teams Score
+}
+
+type OddsResponse struct {
+	Response []struct {
+		Update     string `json:"update"`
+		Bookmakers []struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+			Bets []struct {
+				ID     int `json:"id"`
+				Values []struct {
+					Value interface{} `json:"value"`
+					Odd   string      `json:"odd"`
+				} `json:"values"`
+			} `json:"bets"`
+		} `json:"bookmakers"`
+	} `json:"response"`
+}
+
+func (p *APISportsProvider) FetchOdds(ctx context.Context, fixtureID int) ([]models.Odd, error) {
+	body, err := p.fetchFixture(ctx, endpointOdds, fixtureID)
+	if err != nil {
+		return nil, err
+	}
+	return decodeOdds(fixtureID, body)
+}
+
+func (p *FixtureProvider) FetchOdds(ctx context.Context, fixtureID int) ([]models.Odd, error) {
+	file, err := p.open(fmt.Sprintf("odds_%d.json", fixtureID))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return decodeOdds(fixtureID, file)
+}
+
+func (p *ArchiveProvider) FetchOdds(ctx context.Context, fixtureID int) ([]models.Odd, error) {
+	body, err := p.load(ctx, endpointOdds, fixtureID)
+	if err != nil {
+		return nil, err
+	}
+	return decodeOdds(fixtureID, body)
+}
+
+// decodeOdds оставляет только рынки 1X2, тотал и "обе забьют".
+func decodeOdds(fixtureID int, body io.Reader) ([]models.Odd, error) {
+	var oddsResp OddsResponse
+	if err := json.NewDecoder(body).Decode(&oddsResp); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecode, err)
+	}
+	if len(oddsResp.Response) == 0 {
+		return nil, errEmptyResponse()
+	}
+
+	var odds []models.Odd
+	for _, r := range oddsResp.Response {
+		for _, bookmaker := range r.Bookmakers {
+			for _, bet := range bookmaker.Bets {
+				market, ok := oddsMarkets[bet.ID]
+				if !ok {
+					continue
+				}
+				for _, v := range bet.Values {
+					value, err := strconv.ParseFloat(v.Odd, 64)
+					if err != nil {
+						continue
+					}
+					odds = append(odds, models.Odd{
+						FixtureID:    fixtureID,
+						BookmakerID:  bookmaker.ID,
+						Bookmaker:    bookmaker.Name,
+						Market:       market,
+						Selection:    fmt.Sprintf("%v", v.Value),
+						Value:        value,
+						APIUpdatedAt: r.Update,
+					})
+				}
+			}
+		}
+	}
+	return odds, nil
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"football-data-miner/internal/models"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type PlayerProfilesResponse struct {
+	Paging struct {
+		Current int `json:"current"`
+		Total   int `json:"total"`
+	} `json:"paging"`
+	Response []struct {
+		Player struct {
+			ID        int     `json:"id"`
+			Name      string  `json:"name"`
+			Firstname *string `json:"firstname"`
+			Lastname  *string `json:"lastname"`
+			Birth     struct {
+				Date    *string `json:"date"`
+				Place   *string `json:"place"`
+				Country *string `json:"country"`
+			} `json:"birth"`
+			Nationality *string `json:"nationality"`
+			Height      *string `json:"height"`
+			Weight      *string `json:"weight"`
+			Photo       string  `json:"photo"`
+		} `json:"player"`
+	} `json:"response"`
+}
+
+// FetchPlayerProfiles возвращает одну страницу профилей игроков лиги в сезоне
+// и общее число страниц.
+func (p *APISportsProvider) FetchPlayerProfiles(ctx context.Context, leagueID int, season string, page int) ([]models.Player, int, error) {
+	path := fmt.Sprintf("%s?league=%d&season=%s&page=%d", endpointPlayerProfiles, leagueID, season, page)
+	body, err := p.fetchSeason(ctx, playerProfilesPage(page), path, leagueID, season)
+	if err != nil {
+		return nil, 0, err
+	}
+	return decodePlayerProfiles(body)
+}
+
+func (p *FixtureProvider) FetchPlayerProfiles(ctx context.Context, leagueID int, season string, page int) ([]models.Player, int, error) {
+	file, err := p.open(fmt.Sprintf("players_%d_%s_%d.json", leagueID, season, page))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+	return decodePlayerProfiles(file)
+}
+
+func (p *ArchiveProvider) FetchPlayerProfiles(ctx context.Context, leagueID int, season string, page int) ([]models.Player, int, error) {
+	body, err := p.loadSeason(ctx, playerProfilesPage(page), leagueID, season)
+	if err != nil {
+		return nil, 0, err
+	}
+	return decodePlayerProfiles(body)
+}
+
+// playerProfilesPage — ключ архива для страницы профилей: страницы сезона хранятся отдельно.
+func playerProfilesPage(page int) string {
+	return fmt.Sprintf("%s?page=%d", endpointPlayerProfiles, page)
+}
+
+func decodePlayerProfiles(body io.Reader) ([]models.Player, int, error) {
+	var profilesResp PlayerProfilesResponse
+	if err := json.NewDecoder(body).Decode(&profilesResp); err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrDecode, err)
+	}
+	if len(profilesResp.Response) == 0 {
+		return nil, 0, errEmptyResponse()
+	}
+
+	var players []models.Player
+	for _, r := range profilesResp.Response {
+		p := r.Player
+		players = append(players, models.Player{
+			ID:           p.ID,
+			Fullname:     p.Name,
+			Firstname:    derefString(p.Firstname),
+			Lastname:     derefString(p.Lastname),
+			BirthDate:    p.Birth.Date,
+			BirthPlace:   derefString(p.Birth.Place),
+			BirthCountry: derefString(p.Birth.Country),
+			Nationality:  derefString(p.Nationality),
+			Height:       parseMeasure(p.Height),
+			Weight:       parseMeasure(p.Weight),
+			Photo:        p.Photo,
+		})
+	}
+	return players, profilesResp.Paging.Total, nil
+}
+
+// parseMeasure разбирает рост и вес вида "180 cm" / "75 kg"; nil — значения нет.
+func parseMeasure(value *string) *int {
+	if value == nil {
+		return nil
+	}
+	fields := strings.Fields(*value)
+	if len(fields) == 0 {
+		return nil
+	}
+	num, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return nil
+	}
+	return &num
+}
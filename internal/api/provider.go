@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"football-data-miner/internal/models"
+	"os"
+	"time"
+)
+
+// FootballProvider — источник данных о матчах. Реализации: api-sports (APISportsProvider)
+// и записанные JSON-фикстуры на диске (FixtureProvider).
+type FootballProvider interface {
+	FetchSeasonMatches(ctx context.Context, leagueID int, season string) ([]models.Match, error)
+	FetchStatistics(ctx context.Context, fixtureID int) ([]TeamStatistics, error)
+	FetchLineups(ctx context.Context, fixtureID int) (LineupResponse, error)
+	FetchPlayers(ctx context.Context, fixtureID int) (PlayersResponse, error)
+	FetchEvents(ctx context.Context, fixtureID int) ([]models.MatchEvent, error)
+	FetchInjuries(ctx context.Context, fixtureID int) ([]models.PlayerAbsence, error)
+	FetchOdds(ctx context.Context, fixtureID int) ([]models.Odd, error)
+	FetchStandings(ctx context.Context, leagueID int, season string) ([]models.StandingRow, error)
+	FetchTeams(ctx context.Context, leagueID int, season string) ([]models.Team, []models.Venue, error)
+	FetchPlayerProfiles(ctx context.Context, leagueID int, season string, page int) ([]models.Player, int, error)
+	FetchLeagueSeasons(ctx context.Context, leagueID int) ([]models.LeagueSeason, error)
+}
+
+// NewProviderFromEnv выбирает провайдера по переменной DATA_PROVIDER:
+// "fixtures" — чтение из каталога FIXTURES_DIR, иначе — api-sports с ключами из
+// API_KEYS_FILE (или одним API_KEY по API_BASE_URL).
+// Для api-sports HTTP_CASSETTE_MODE=record|replay и HTTP_CASSETTE_DIR включают кассету.
+func NewProviderFromEnv() (FootballProvider, error) {
+	switch os.Getenv("DATA_PROVIDER") {
+	case "fixtures":
+		dir := os.Getenv("FIXTURES_DIR")
+		if dir == "" {
+			return nil, fmt.Errorf("FIXTURES_DIR не установлен")
+		}
+		return NewFixtureProvider(dir), nil
+	case "", "api-sports":
+		if timeout := os.Getenv("API_TIMEOUT"); timeout != "" {
+			d, err := time.ParseDuration(timeout)
+			if err != nil {
+				return nil, fmt.Errorf("некорректный API_TIMEOUT: %v", err)
+			}
+			SetRequestTimeout(d)
+		}
+		if mode := os.Getenv("HTTP_CASSETTE_MODE"); mode != "" {
+			if err := UseCassette(mode, os.Getenv("HTTP_CASSETTE_DIR")); err != nil {
+				return nil, err
+			}
+		}
+		pool, err := keyPoolFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		UseKeyPool(pool)
+		return NewAPISportsProvider(), nil
+	default:
+		return nil, fmt.Errorf("неизвестный DATA_PROVIDER: %s", os.Getenv("DATA_PROVIDER"))
+	}
+}
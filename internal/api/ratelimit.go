@@ -0,0 +1,161 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Заголовки лимитов api-sports: поминутный и суточный.
+const (
+	headerMinuteRemaining = "X-RateLimit-Remaining"
+	headerDayRemaining    = "X-RateLimit-Requests-Remaining"
+
+	// Сколько суточных запросов оставляем в запасе.
+	dailyReserve = 3
+)
+
+// RateLimiter распределяет запросы по оставшейся квоте. Остатки берутся из заголовков
+// каждого ответа; при исчерпании квоты Wait ждет сброса окна (минута или сутки UTC).
+type RateLimiter struct {
+	mu          sync.Mutex
+	minInterval time.Duration
+	lastRequest time.Time
+
+	minuteRemaining int // -1 — неизвестно
+	minuteReset     time.Time
+	dayRemaining    int
+	dayReset        time.Time
+}
+
+func NewRateLimiter(minInterval time.Duration) *RateLimiter {
+	return &RateLimiter{
+		minInterval:     minInterval,
+		minuteRemaining: -1,
+		dayRemaining:    -1,
+	}
+}
+
+// Wait блокируется, пока следующий запрос не уложится в квоту, и резервирует его.
+// Возвращает ошибку, если ctx отменен раньше.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		delay := l.delay(now)
+		if delay <= 0 {
+			l.lastRequest = now
+			if l.minuteRemaining > 0 {
+				l.minuteRemaining--
+			}
+			if l.dayRemaining > 0 {
+				l.dayRemaining--
+			}
+			l.mu.Unlock()
+			return nil
+		}
+		l.mu.Unlock()
+
+		if delay > time.Minute {
+			fmt.Printf("Квота запросов исчерпана. Ждем до %s\n", now.Add(delay).Format(time.RFC3339))
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+func (l *RateLimiter) delay(now time.Time) time.Duration {
+	if l.dayRemaining >= 0 && !now.Before(l.dayReset) {
+		l.dayRemaining = -1
+	}
+	if l.minuteRemaining >= 0 && !now.Before(l.minuteReset) {
+		l.minuteRemaining = -1
+	}
+
+	if l.dayRemaining >= 0 && l.dayRemaining <= dailyReserve {
+		return l.dayReset.Sub(now)
+	}
+	if l.minuteRemaining == 0 {
+		return l.minuteReset.Sub(now)
+	}
+
+	interval := l.minInterval
+	if l.minuteRemaining > 0 {
+		// Равномерно распределяем оставшиеся запросы до конца минутного окна.
+		if spread := l.minuteReset.Sub(now) / time.Duration(l.minuteRemaining); spread > interval {
+			interval = spread
+		}
+	}
+	return l.lastRequest.Add(interval).Sub(now)
+}
+
+// Headroom — запас суточной квоты сверх резерва; math.MaxInt32, если квота еще неизвестна.
+// Ноль и меньше — квота исчерпана до сброса.
+func (l *RateLimiter) Headroom() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.dayRemaining < 0 || !time.Now().Before(l.dayReset) {
+		return math.MaxInt32
+	}
+	return l.dayRemaining - dailyReserve
+}
+
+// ResetAt — момент сброса суточной квоты; нулевое время, если квота еще неизвестна.
+func (l *RateLimiter) ResetAt() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.dayRemaining < 0 {
+		return time.Time{}
+	}
+	return l.dayReset
+}
+
+// Exhaust помечает суточную квоту исчерпанной до ближайшей полуночи UTC.
+func (l *RateLimiter) Exhaust() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.dayRemaining = 0
+	l.dayReset = nextUTCMidnight(time.Now())
+}
+
+// Update обновляет остатки квоты по заголовкам ответа.
+func (l *RateLimiter) Update(header http.Header) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+
+	if remaining, ok := headerInt(header, headerMinuteRemaining); ok {
+		if l.minuteRemaining < 0 || !now.Before(l.minuteReset) {
+			l.minuteReset = now.Add(time.Minute)
+		}
+		l.minuteRemaining = remaining
+	}
+	if remaining, ok := headerInt(header, headerDayRemaining); ok {
+		l.dayRemaining = remaining
+		l.dayReset = nextUTCMidnight(now)
+	}
+}
+
+func headerInt(header http.Header, name string) (int, bool) {
+	value := header.Get(name)
+	if value == "" {
+		return 0, false
+	}
+	num, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false
+	}
+	return num, true
+}
+
+func nextUTCMidnight(now time.Time) time.Time {
+	return now.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterUpdate(t *testing.T) {
+	tests := []struct {
+		name         string
+		header       map[string]string
+		wantHeadroom int
+		wantWait     bool
+	}{
+		{"нет заголовков", nil, math.MaxInt32, false},
+		{"суточный остаток", map[string]string{headerDayRemaining: "100"}, 100 - dailyReserve, false},
+		{"суточный резерв", map[string]string{headerDayRemaining: "3"}, 0, true},
+		{"минутная квота исчерпана", map[string]string{headerMinuteRemaining: "0", headerDayRemaining: "50"}, 50 - dailyReserve, true},
+		{"нечисловой заголовок", map[string]string{headerDayRemaining: "n/a"}, math.MaxInt32, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewRateLimiter(0)
+			header := http.Header{}
+			for name, value := range tt.header {
+				header.Set(name, value)
+			}
+			limiter.Update(header)
+
+			if got := limiter.Headroom(); got != tt.wantHeadroom {
+				t.Errorf("Headroom() = %d, ожидалось %d", got, tt.wantHeadroom)
+			}
+			if wait := limiter.delay(time.Now()) > 0; wait != tt.wantWait {
+				t.Errorf("ожидание перед запросом %t, ожидалось %t", wait, tt.wantWait)
+			}
+		})
+	}
+}
+
+func TestRateLimiterExhaust(t *testing.T) {
+	limiter := NewRateLimiter(0)
+	if !limiter.ResetAt().IsZero() {
+		t.Fatalf("ResetAt() до первого ответа = %s, ожидалось нулевое время", limiter.ResetAt())
+	}
+	limiter.Exhaust()
+	if got := limiter.Headroom(); got > 0 {
+		t.Errorf("Headroom() после Exhaust = %d, ожидалось не больше нуля", got)
+	}
+	if reset := limiter.ResetAt(); !reset.Equal(nextUTCMidnight(time.Now())) {
+		t.Errorf("ResetAt() = %s, ожидалась ближайшая полночь UTC", reset)
+	}
+}
@@ -1,136 +1,311 @@
 package api
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"football-data-miner/internal/models"
 	"io"
 	"net/http"
-	"os"
 	"strconv"
 	"strings"
 	"time"
 )
 
-var lastRequestTime time.Time = time.Now()
+var httpClient = &http.Client{}
 
-func FetchStatistics(fixtureID int) ([]TeamStatistics, bool, error) {
-	endpoint := fmt.Sprintf("%s/fixtures/statistics?fixture=%d", os.Getenv("API_BASE_URL"), fixtureID)
-	resp, err := makeRequest(endpoint)
+// requestTimeout ограничивает одну попытку запроса, включая чтение тела ответа.
+var requestTimeout = 30 * time.Second
+
+// SetRequestTimeout меняет таймаут одной попытки запроса к API.
+func SetRequestTimeout(timeout time.Duration) {
+	requestTimeout = timeout
+}
+
+// APISportsProvider ходит в api-sports через пул ключей (см. UseKeyPool).
+type APISportsProvider struct{}
+
+func NewAPISportsProvider() *APISportsProvider {
+	return &APISportsProvider{}
+}
+
+func (p *APISportsProvider) FetchStatistics(ctx context.Context, fixtureID int) ([]TeamStatistics, error) {
+	body, err := p.fetchFixture(ctx, endpointStatistics, fixtureID)
 	if err != nil {
-		return nil, false, err
+		return nil, err
 	}
-	defer resp.Body.Close()
+	return decodeStatistics(body)
+}
 
-	var statsResponse struct {
-		Response []TeamStatistics `json:"response"`
+func (p *APISportsProvider) FetchLineups(ctx context.Context, fixtureID int) (LineupResponse, error) {
+	body, err := p.fetchFixture(ctx, endpointLineups, fixtureID)
+	if err != nil {
+		return LineupResponse{}, err
 	}
-	remainingStr := resp.Header.Get("x-ratelimit-requests-remaining")
-	remaining, err := strconv.Atoi(remainingStr)
+	return decodeLineups(body)
+}
+
+func (p *APISportsProvider) FetchPlayers(ctx context.Context, fixtureID int) (PlayersResponse, error) {
+	body, err := p.fetchFixture(ctx, endpointPlayers, fixtureID)
 	if err != nil {
-		return []TeamStatistics{}, false, fmt.Errorf("Ошибка парсинга x-ratelimit-requests-remaining: %v", err)
+		return PlayersResponse{}, err
 	}
-	err = json.NewDecoder(resp.Body).Decode(&statsResponse)
-	if remaining < 4 {
-		fmt.Println("Меньше 4 запросов осталось. Завершаем обработку")
-		return statsResponse.Response, false, nil
+	return decodePlayers(body)
+}
+
+func (p *APISportsProvider) FetchSeasonMatches(ctx context.Context, leagueID int, season string) ([]models.Match, error) {
+	path := fmt.Sprintf("%s?league=%d&season=%s", endpointFixtures, leagueID, season)
+	body, err := p.fetchSeason(ctx, endpointFixtures, path, leagueID, season)
+	if err != nil {
+		return nil, err
 	}
-	return statsResponse.Response, true, err
+	return decodeSeasonMatches(body)
 }
 
-func FetchLineups(fixtureID int) (LineupResponse, error) {
-	endpoint := fmt.Sprintf("%s/fixtures/lineups?fixture=%d", os.Getenv("API_BASE_URL"), fixtureID)
-	resp, err := makeRequest(endpoint)
+// fetchFixture запрашивает эндпоинт по матчу и архивирует тело ответа.
+func (p *APISportsProvider) fetchFixture(ctx context.Context, endpoint string, fixtureID int) (io.Reader, error) {
+	body, err := readResponse(ctx, fmt.Sprintf("%s?fixture=%d", endpoint, fixtureID))
 	if err != nil {
-		return LineupResponse{}, err
+		return nil, err
 	}
-	defer resp.Body.Close()
+	archivePayload(ctx, endpoint, fixtureID, body)
+	return bytes.NewReader(body), nil
+}
 
-	var lineups LineupResponse
-	err = json.NewDecoder(resp.Body).Decode(&lineups)
-	return lineups, err
+// fetchSeason запрашивает path и архивирует тело ответа под ключом endpoint, лига, сезон.
+// Пустой season — ответ по лиге целиком.
+func (p *APISportsProvider) fetchSeason(ctx context.Context, endpoint, path string, leagueID int, season string) (io.Reader, error) {
+	body, err := readResponse(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	archiveSeasonPayload(ctx, endpoint, leagueID, season, body)
+	return bytes.NewReader(body), nil
 }
 
-func FetchPlayers(fixtureID int) (PlayersResponse, error) {
-	var players PlayersResponse
-	endpoint := fmt.Sprintf("%s/fixtures/players?fixture=%d", os.Getenv("API_BASE_URL"), fixtureID)
-	resp, err := makeRequest(endpoint)
+func readResponse(ctx context.Context, path string) ([]byte, error) {
+	resp, err := makeRequest(ctx, path)
 	if err != nil {
-		return PlayersResponse{}, err
+		return nil, err
 	}
 	defer resp.Body.Close()
-	err = json.NewDecoder(resp.Body).Decode(&players)
+
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return PlayersResponse{}, fmt.Errorf("Ошибка декодирования данных: %v", err)
+		return nil, fmt.Errorf("%w: ошибка чтения ответа: %v", ErrUpstream, err)
 	}
+	return body, nil
+}
 
-	return players, nil
+func decodeStatistics(body io.Reader) ([]TeamStatistics, error) {
+	var statsResponse struct {
+		Response []TeamStatistics `json:"response"`
+	}
+	if err := json.NewDecoder(body).Decode(&statsResponse); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecode, err)
+	}
+	if len(statsResponse.Response) == 0 {
+		return nil, errEmptyResponse()
+	}
+	return statsResponse.Response, nil
 }
 
-func FetchSeasonMatches(leagueID int, season string) ([]models.Match, error) {
-	endpoint := fmt.Sprintf("%s/fixtures?league=%d&season=%s", os.Getenv("API_BASE_URL"), leagueID, season)
-	resp, err := makeRequest(endpoint)
+func decodeLineups(body io.Reader) (LineupResponse, error) {
+	var lineups LineupResponse
+	if err := json.NewDecoder(body).Decode(&lineups); err != nil {
+		return LineupResponse{}, fmt.Errorf("%w: %v", ErrDecode, err)
+	}
+	if len(lineups.Response) == 0 {
+		return LineupResponse{}, errEmptyResponse()
+	}
+	return lineups, nil
+}
+
+func decodePlayers(body io.Reader) (PlayersResponse, error) {
+	var players PlayersResponse
+	err := json.NewDecoder(body).Decode(&players)
 	if err != nil {
-		return nil, err
+		return PlayersResponse{}, fmt.Errorf("%w: %v", ErrDecode, err)
 	}
-	defer resp.Body.Close()
+	if len(players.Response) == 0 {
+		return PlayersResponse{}, errEmptyResponse()
+	}
+	return players, nil
+}
 
+func decodeSeasonMatches(body io.Reader) ([]models.Match, error) {
 	var matchesResponse models.MatchesOfSeason
-	err = json.NewDecoder(resp.Body).Decode(&matchesResponse)
+	err := json.NewDecoder(body).Decode(&matchesResponse)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка декодирования JSON: %v", err)
+		return nil, fmt.Errorf("%w: %v", ErrDecode, err)
+	}
+	if len(matchesResponse.Response) == 0 {
+		return nil, errEmptyResponse()
 	}
 
 	var matches []models.Match
 	for _, m := range matchesResponse.Response {
 		matches = append(matches, models.Match{
 			ID:            m.Fixture.ID,
+			LeagueID:      m.League.ID,
 			Date:          m.Fixture.Date,
 			HomeTeamID:    m.Teams.Home.ID,
 			AwayTeamID:    m.Teams.Away.ID,
 			HomeTeamName:  m.Teams.Home.Name,
 			AwayTeamName:  m.Teams.Away.Name,
-			HomeScore:     toIntPtr(safeInt(m.Score.Fulltime.Home)),
-			AwayScore:     toIntPtr(safeInt(m.Score.Fulltime.Away)),
+			HomeScore:     m.Score.Fulltime.Home,
+			AwayScore:     m.Score.Fulltime.Away,
 			HomeCoachID:   0,
 			AwayCoachID:   0,
 			HomeFormation: "",
 			AwayFormation: "",
 			Round:         m.League.Round,
+			Status:        m.Fixture.Status.Short,
+			HomeHalftime:  m.Score.Halftime.Home,
+			AwayHalftime:  m.Score.Halftime.Away,
+			HomeExtratime: m.Score.Extratime.Home,
+			AwayExtratime: m.Score.Extratime.Away,
+			HomePenalty:   m.Score.Penalty.Home,
+			AwayPenalty:   m.Score.Penalty.Away,
+			VenueID:       m.Fixture.Venue.ID,
+			VenueName:     m.Fixture.Venue.Name,
+			VenueCity:     m.Fixture.Venue.City,
+			Referee:       derefString(m.Fixture.Referee),
 		})
 	}
 
 	return matches, nil
 }
-func makeRequest(url string) (*http.Response, error) {
-	time.Sleep(300 * time.Millisecond)
-	//time.Sleep(1 * time.Second)
-	req, err := http.NewRequest("GET", url, nil)
+
+// makeRequest запрашивает путь относительно базового адреса выбранного ключа.
+func makeRequest(ctx context.Context, path string) (*http.Response, error) {
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			delay := backoff(attempt - 1)
+			fmt.Printf("Повтор запроса %s через %s (попытка %d): %v\n", path, delay, attempt+1, lastErr)
+			if err := sleepContext(ctx, delay); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, status, err := doRequest(ctx, path)
+		if err == nil {
+			return resp, nil
+		}
+		if ctx.Err() != nil || !isRetryable(err, status) {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// doRequest выполняет один запрос с таймаутом requestTimeout. Тело ответа читается целиком,
+// чтобы распознать ошибки, которые api-sports возвращает со статусом 200 в поле "errors".
+func doRequest(ctx context.Context, path string) (*http.Response, int, error) {
+	if keyPool == nil {
+		return nil, 0, fmt.Errorf("пул ключей API не задан")
+	}
+	key, err := keyPool.acquire()
 	if err != nil {
-		return nil, fmt.Errorf("ошибка при создании запроса: %v", err)
+		return nil, 0, err
 	}
 
-	apiKey := os.Getenv("API_KEY")
-	if apiKey == "" {
-		return nil, fmt.Errorf("API_KEY не установлен")
+	replaying := isReplaying()
+	if !replaying {
+		if err := key.limiter.Wait(ctx); err != nil {
+			return nil, 0, err
+		}
 	}
 
-	req.Header.Set("X-RapidAPI-Key", apiKey)
-	req.Header.Set("X-RapidAPI-Host", "v3.football.api-sports.io")
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", key.BaseURL+"/"+path, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("ошибка при создании запроса: %v", err)
+	}
+	key.setHeaders(req)
+
+	resp, err := httpClient.Do(req)
+	if errors.Is(err, errCassetteMiss) {
+		// Не ErrNotFound: загрузчик принял бы пропуск записи за отсутствие данных у матча
+		return nil, 0, err
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: ошибка при выполнении запроса: %v", ErrUpstream, err)
+	}
+	if !replaying {
+		key.limiter.Update(resp.Header)
+	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
 	if err != nil {
-		return nil, fmt.Errorf("ошибка при выполнении запроса: %v", err)
+		return nil, 0, fmt.Errorf("%w: ошибка чтения ответа: %v", ErrUpstream, err)
 	}
 
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		keyPool.disable(key, fmt.Sprintf("статус %d", resp.StatusCode))
+		return nil, resp.StatusCode, fmt.Errorf("%w: %s: статус %d", errKeyRejected, key.Name, resp.StatusCode)
+	}
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		defer resp.Body.Close()
-		return nil, fmt.Errorf("API вернул статус %d: %s", resp.StatusCode, string(body))
+		return nil, resp.StatusCode, statusError(resp.StatusCode, string(body))
+	}
+	if err := checkErrorsField(body); err != nil {
+		switch {
+		case errors.Is(err, errKeyRejected):
+			keyPool.disable(key, err.Error())
+		case errors.Is(err, errDailyQuota):
+			key.limiter.Exhaust()
+		}
+		return nil, resp.StatusCode, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, resp.StatusCode, nil
+}
+
+// sleepContext ждет delay или отмены ctx.
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// checkErrorsField разбирает поле "errors": пустой массив — успех, объект — ошибка API.
+func checkErrorsField(body []byte) error {
+	var envelope struct {
+		Errors json.RawMessage `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return fmt.Errorf("%w: %v", ErrDecode, err)
 	}
 
-	return resp, nil
+	var apiErrors map[string]string
+	if json.Unmarshal(envelope.Errors, &apiErrors) != nil || len(apiErrors) == 0 {
+		return nil
+	}
+	if _, ok := apiErrors["rateLimit"]; ok {
+		return fmt.Errorf("%w: %v", ErrRateLimited, apiErrors)
+	}
+	if _, ok := apiErrors["requests"]; ok {
+		return fmt.Errorf("%w: %w: %v", ErrRateLimited, errDailyQuota, apiErrors)
+	}
+	if _, ok := apiErrors["token"]; ok {
+		return fmt.Errorf("%w: %v", errKeyRejected, apiErrors)
+	}
+	if _, ok := apiErrors["access"]; ok {
+		return fmt.Errorf("%w: %v", errKeyRejected, apiErrors)
+	}
+	return fmt.Errorf("%w: %v", ErrUpstream, apiErrors)
 }
 
 func safeInt(value interface{}) int {
@@ -150,7 +325,3 @@ func safeInt(value interface{}) int {
 		return 0
 	}
 }
-
-func toIntPtr(value int) *int {
-	return &value
-}
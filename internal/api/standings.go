@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"football-data-miner/internal/models"
+	"io"
+)
+
+type standingsRecord struct {
+	Played int `json:"played"`
+	Win    int `json:"win"`
+	Draw   int `json:"draw"`
+	Lose   int `json:"lose"`
+	Goals  struct {
+		For     int `json:"for"`
+		Against int `json:"against"`
+	} `json:"goals"`
+}
+
+type StandingsResponse struct {
+	Response []struct {
+		League struct {
+			ID        int `json:"id"`
+			Standings [][]struct {
+				Rank int `json:"rank"`
+				Team struct {
+					ID   int    `json:"id"`
+					Name string `json:"name"`
+				} `json:"team"`
+				Points      int             `json:"points"`
+				GoalsDiff   int             `json:"goalsDiff"`
+				Group       string          `json:"group"`
+				Form        string          `json:"form"`
+				Description string          `json:"description"`
+				All         standingsRecord `json:"all"`
+				Home        standingsRecord `json:"home"`
+				Away        standingsRecord `json:"away"`
+				Update      string          `json:"update"`
+			} `json:"standings"`
+		} `json:"league"`
+	} `json:"response"`
+}
+
+func (p *APISportsProvider) FetchStandings(ctx context.Context, leagueID int, season string) ([]models.StandingRow, error) {
+	path := fmt.Sprintf("%s?league=%d&season=%s", endpointStandings, leagueID, season)
+	body, err := p.fetchSeason(ctx, endpointStandings, path, leagueID, season)
+	if err != nil {
+		return nil, err
+	}
+	return decodeStandings(leagueID, season, body)
+}
+
+func (p *FixtureProvider) FetchStandings(ctx context.Context, leagueID int, season string) ([]models.StandingRow, error) {
+	file, err := p.open(fmt.Sprintf("standings_%d_%s.json", leagueID, season))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return decodeStandings(leagueID, season, file)
+}
+
+func (p *ArchiveProvider) FetchStandings(ctx context.Context, leagueID int, season string) ([]models.StandingRow, error) {
+	body, err := p.loadSeason(ctx, endpointStandings, leagueID, season)
+	if err != nil {
+		return nil, err
+	}
+	return decodeStandings(leagueID, season, body)
+}
+
+// decodeStandings разворачивает таблицы (по одной на группу) в плоский список строк.
+// Очки уже учитывают снятия, поэтому таблица совпадает с официальной.
+func decodeStandings(leagueID int, season string, body io.Reader) ([]models.StandingRow, error) {
+	var standingsResp StandingsResponse
+	if err := json.NewDecoder(body).Decode(&standingsResp); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecode, err)
+	}
+	if len(standingsResp.Response) == 0 {
+		return nil, errEmptyResponse()
+	}
+
+	var rows []models.StandingRow
+	for _, r := range standingsResp.Response {
+		for _, table := range r.League.Standings {
+			for _, s := range table {
+				rows = append(rows, models.StandingRow{
+					LeagueID:     leagueID,
+					Season:       season,
+					TeamID:       s.Team.ID,
+					TeamName:     s.Team.Name,
+					Group:        s.Group,
+					Rank:         s.Rank,
+					Points:       s.Points,
+					GoalsDiff:    s.GoalsDiff,
+					Played:       s.All.Played,
+					Win:          s.All.Win,
+					Draw:         s.All.Draw,
+					Lose:         s.All.Lose,
+					GoalsFor:     s.All.Goals.For,
+					GoalsAgainst: s.All.Goals.Against,
+					HomePlayed:   s.Home.Played,
+					AwayPlayed:   s.Away.Played,
+					Form:         s.Form,
+					Description:  s.Description,
+					UpdatedAt:    s.Update,
+				})
+			}
+		}
+	}
+	return rows, nil
+}
@@ -2,10 +2,10 @@ package api
 
 import (
 	"fmt"
-	"football-data-miner/internal/cache"
 	"football-data-miner/internal/models"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 type TeamStatistics struct {
@@ -18,14 +18,12 @@ type TeamStatistics struct {
 	} `json:"statistics"`
 }
 
-func ParseStatistics(fixtureID int, teamStats []TeamStatistics) (models.MatchStatistics, error) {
-	match, err := cache.GetMatchFromRedis(fixtureID)
-	if err != nil {
-		return models.MatchStatistics{}, err
-	}
-
+// ParseMatchStatistics разбирает статистику матча; хозяева и гости определяются по match.
+// leagueID — лига, под которой учитываются нераспознанные типы статистики: в старых
+// и загруженных из БД матчах match.LeagueID может быть не заполнен.
+func ParseMatchStatistics(leagueID int, match models.Match, teamStats []TeamStatistics) models.MatchStatistics {
 	stats := models.MatchStatistics{
-		MatchID: fixtureID,
+		MatchID: match.ID,
 	}
 
 	for _, teamStat := range teamStats {
@@ -145,11 +143,75 @@ func ParseStatistics(fixtureID int, teamStats []TeamStatistics) (models.MatchSta
 				} else {
 					stats.AwayPassesPercentage = passesPct
 				}
+			case "expected_goals":
+				val := parseDecimal(s.Value)
+				if isHome {
+					stats.HomeExpectedGoals = val
+				} else {
+					stats.AwayExpectedGoals = val
+				}
+			case "goals_prevented":
+				val := parseDecimal(s.Value)
+				if isHome {
+					stats.HomeGoalsPrevented = val
+				} else {
+					stats.AwayGoalsPrevented = val
+				}
+			default:
+				recordUnknownStatistic(leagueID, s.Type)
 			}
 		}
 	}
 
-	return stats, nil
+	return stats
+}
+
+// parseDecimal разбирает дробные значения ("1.23" или число); nil — значения нет.
+func parseDecimal(value interface{}) *float64 {
+	switch v := value.(type) {
+	case float64:
+		return &v
+	case string:
+		num, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil
+		}
+		return &num
+	default:
+		return nil
+	}
+}
+
+var (
+	unknownStatsMu sync.Mutex
+	unknownStats   = map[int]map[string]int{}
+)
+
+// recordUnknownStatistic считает нераспознанные типы статистики по лигам,
+// чтобы изменения в ответах API не терялись молча.
+func recordUnknownStatistic(leagueID int, statType string) {
+	unknownStatsMu.Lock()
+	defer unknownStatsMu.Unlock()
+
+	if unknownStats[leagueID] == nil {
+		unknownStats[leagueID] = map[string]int{}
+	}
+	if unknownStats[leagueID][statType] == 0 {
+		fmt.Printf("Неизвестный тип статистики %q (лига %d)\n", statType, leagueID)
+	}
+	unknownStats[leagueID][statType]++
+}
+
+// PrintUnknownStatistics выводит сводку нераспознанных типов статистики по лигам.
+func PrintUnknownStatistics() {
+	unknownStatsMu.Lock()
+	defer unknownStatsMu.Unlock()
+
+	for leagueID, types := range unknownStats {
+		for statType, count := range types {
+			fmt.Printf("Лига %d: неизвестный тип статистики %q встретился %d раз\n", leagueID, statType, count)
+		}
+	}
 }
 
 func parsePercentage(value interface{}) int {
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"football-data-miner/internal/models"
+	"testing"
+)
+
+func TestParseMatchStatistics(t *testing.T) {
+	teamStats, err := NewFixtureProvider("testdata").FetchStatistics(context.Background(), 1035037)
+	if err != nil {
+		t.Fatalf("FetchStatistics: %v", err)
+	}
+	match := models.Match{ID: 1035037, HomeTeamID: 44, AwayTeamID: 50}
+	unknownBefore := unknownStats[39]["Hit Woodwork"]
+	stats := ParseMatchStatistics(39, match, teamStats)
+
+	tests := []struct {
+		name string
+		got  int
+		want int
+	}{
+		{"MatchID", stats.MatchID, 1035037},
+		{"HomeBallPossession", stats.HomeBallPossession, 35},
+		{"AwayBallPossession", stats.AwayBallPossession, 65},
+		{"HomeShotsOnGoal", stats.HomeShotsOnGoal, 1},
+		{"AwayShotsOnGoal", stats.AwayShotsOnGoal, 8},
+		{"AwayTotalShots", stats.AwayTotalShots, 17},
+		{"HomeOffsides (null)", stats.HomeOffsides, 0},
+		{"HomeRedCards", stats.HomeRedCards, 1},
+		{"AwayYellowCards", stats.AwayYellowCards, 2},
+		{"HomeGoalkeeperSaves", stats.HomeGoalkeeperSaves, 5},
+		{"AwayTotalPasses", stats.AwayTotalPasses, 681},
+		{"AwayPassesPercentage", stats.AwayPassesPercentage, 89},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %d, ожидалось %d", tt.name, tt.got, tt.want)
+		}
+	}
+
+	decimals := []struct {
+		name string
+		got  *float64
+		want *float64
+	}{
+		{"HomeExpectedGoals", stats.HomeExpectedGoals, float(0.32)},
+		{"AwayExpectedGoals", stats.AwayExpectedGoals, float(2.33)},
+		{"HomeGoalsPrevented", stats.HomeGoalsPrevented, float(0)},
+		{"AwayGoalsPrevented (null)", stats.AwayGoalsPrevented, nil},
+	}
+	for _, tt := range decimals {
+		switch {
+		case tt.want == nil && tt.got != nil:
+			t.Errorf("%s = %v, ожидалось nil", tt.name, *tt.got)
+		case tt.want != nil && (tt.got == nil || *tt.got != *tt.want):
+			t.Errorf("%s = %v, ожидалось %v", tt.name, tt.got, *tt.want)
+		}
+	}
+
+	if got := unknownStats[39]["Hit Woodwork"] - unknownBefore; got != 1 {
+		t.Errorf("неизвестная статистика в лиге 39 учтена %d раз, ожидалось 1", got)
+	}
+}
+
+func float(v float64) *float64 {
+	return &v
+}
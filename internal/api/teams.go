@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"football-data-miner/internal/models"
+	"io"
+)
+
+type TeamsResponse struct {
+	Response []struct {
+		Team struct {
+			ID       int     `json:"id"`
+			Name     string  `json:"name"`
+			Code     *string `json:"code"`
+			Country  string  `json:"country"`
+			Founded  *int    `json:"founded"`
+			National bool    `json:"national"`
+		} `json:"team"`
+		Venue struct {
+			ID       *int    `json:"id"`
+			Name     string  `json:"name"`
+			Address  *string `json:"address"`
+			City     string  `json:"city"`
+			Capacity *int    `json:"capacity"`
+			Surface  *string `json:"surface"`
+		} `json:"venue"`
+	} `json:"response"`
+}
+
+// FetchTeams возвращает команды лиги в сезоне вместе с их домашними стадионами.
+func (p *APISportsProvider) FetchTeams(ctx context.Context, leagueID int, season string) ([]models.Team, []models.Venue, error) {
+	path := fmt.Sprintf("%s?league=%d&season=%s", endpointTeams, leagueID, season)
+	body, err := p.fetchSeason(ctx, endpointTeams, path, leagueID, season)
+	if err != nil {
+		return nil, nil, err
+	}
+	return decodeTeams(body)
+}
+
+func (p *FixtureProvider) FetchTeams(ctx context.Context, leagueID int, season string) ([]models.Team, []models.Venue, error) {
+	file, err := p.open(fmt.Sprintf("teams_%d_%s.json", leagueID, season))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+	return decodeTeams(file)
+}
+
+func (p *ArchiveProvider) FetchTeams(ctx context.Context, leagueID int, season string) ([]models.Team, []models.Venue, error) {
+	body, err := p.loadSeason(ctx, endpointTeams, leagueID, season)
+	if err != nil {
+		return nil, nil, err
+	}
+	return decodeTeams(body)
+}
+
+func decodeTeams(body io.Reader) ([]models.Team, []models.Venue, error) {
+	var teamsResp TeamsResponse
+	if err := json.NewDecoder(body).Decode(&teamsResp); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrDecode, err)
+	}
+	if len(teamsResp.Response) == 0 {
+		return nil, nil, errEmptyResponse()
+	}
+
+	var teams []models.Team
+	var venues []models.Venue
+	for _, r := range teamsResp.Response {
+		team := models.Team{
+			ID:       r.Team.ID,
+			Fullname: r.Team.Name,
+			Code:     derefString(r.Team.Code),
+			Country:  r.Team.Country,
+			Founded:  r.Team.Founded,
+			National: r.Team.National,
+			VenueID:  r.Venue.ID,
+		}
+		teams = append(teams, team)
+
+		if r.Venue.ID != nil {
+			venues = append(venues, models.Venue{
+				ID:       *r.Venue.ID,
+				Name:     r.Venue.Name,
+				Address:  derefString(r.Venue.Address),
+				City:     r.Venue.City,
+				Capacity: derefInt(r.Venue.Capacity),
+				Surface:  derefString(r.Venue.Surface),
+			})
+		}
+	}
+	return teams, venues, nil
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func derefInt(value *int) int {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"football-data-miner/internal/models"
+	"os"
+	"time"
+)
+
+// Cache хранит матчи сезонов, которые сейчас в обработке, и множества уже обработанных матчей.
+// Источник истины о прогрессе — ingestion_progress в БД; множества обработанных — ее копия,
+// по которой загрузчик пропускает матчи без обращения к БД. Восстанавливается командой resync_cache.
+// Реализации: Redis (RedisCache) и память процесса (MemoryCache).
+type Cache interface {
+	CachedSeasons(ctx context.Context) ([]models.Season, error)
+	GetSeasonMatches(ctx context.Context, leagueID int, season string) ([]models.Match, error)
+	CacheSeasonMatches(ctx context.Context, leagueID int, season string, matches []models.Match) error
+	MarkMatchAsProcessed(ctx context.Context, leagueID int, season string, matchID int)
+	IsMatchProcessed(ctx context.Context, leagueID int, season string, matchID int) (bool, error)
+	SetProcessedMatches(ctx context.Context, leagueID int, season string, matchIDs []int) error
+	ClearSeason(ctx context.Context, leagueID int, season string) error
+
+	// Аренда сезона: пока owner продлевает ее раньше истечения ttl, другие процессы
+	// этот сезон не берут. Аренда упавшего процесса истекает сама.
+	AcquireLease(ctx context.Context, leagueID int, season, owner string, ttl time.Duration) (bool, error)
+	RenewLease(ctx context.Context, leagueID int, season, owner string, ttl time.Duration) (bool, error)
+	ReleaseLease(ctx context.Context, leagueID int, season, owner string) error
+
+	Close() error
+}
+
+// NewCacheFromEnv выбирает кэш по переменной CACHE_BACKEND: "memory" — в памяти процесса
+// (состояние теряется при выходе), иначе — Redis с настройками из RedisConfigFromEnv.
+func NewCacheFromEnv(ctx context.Context) (Cache, error) {
+	switch os.Getenv("CACHE_BACKEND") {
+	case "memory":
+		return NewMemoryCache(), nil
+	case "", "redis":
+		config, err := RedisConfigFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		return NewRedisCache(ctx, config)
+	default:
+		return nil, fmt.Errorf("неизвестный CACHE_BACKEND: %s", os.Getenv("CACHE_BACKEND"))
+	}
+}
@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"football-data-miner/internal/models"
+	"sync"
+	"time"
+)
+
+// MemoryCache — кэш в памяти процесса для тестов и локального запуска без Redis.
+// TTL не поддерживается: данные живут до выхода. Аренды действуют только внутри процесса.
+type MemoryCache struct {
+	mu        sync.Mutex
+	seasons   map[models.Season][]models.Match
+	processed map[models.Season]map[int]bool
+	leases    map[models.Season]memoryLease
+}
+
+type memoryLease struct {
+	owner   string
+	expires time.Time
+}
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		seasons:   make(map[models.Season][]models.Match),
+		processed: make(map[models.Season]map[int]bool),
+		leases:    make(map[models.Season]memoryLease),
+	}
+}
+
+func (c *MemoryCache) Close() error {
+	return nil
+}
+
+func (c *MemoryCache) CachedSeasons(ctx context.Context) ([]models.Season, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	seasons := make([]models.Season, 0, len(c.seasons))
+	for season := range c.seasons {
+		seasons = append(seasons, season)
+	}
+	return seasons, nil
+}
+
+func (c *MemoryCache) GetSeasonMatches(ctx context.Context, leagueID int, season string) ([]models.Match, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	matches, ok := c.seasons[models.Season{LeagueID: leagueID, Season: season}]
+	if !ok {
+		return nil, fmt.Errorf("ошибка при получении матчей: сезон лиги %d, сезон %s не в кэше", leagueID, season)
+	}
+	return append([]models.Match(nil), matches...), nil
+}
+
+func (c *MemoryCache) CacheSeasonMatches(ctx context.Context, leagueID int, season string, matches []models.Match) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.seasons[models.Season{LeagueID: leagueID, Season: season}] = append([]models.Match(nil), matches...)
+	return nil
+}
+
+func (c *MemoryCache) MarkMatchAsProcessed(ctx context.Context, leagueID int, season string, matchID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := models.Season{LeagueID: leagueID, Season: season}
+	if c.processed[key] == nil {
+		c.processed[key] = make(map[int]bool)
+	}
+	c.processed[key][matchID] = true
+	fmt.Printf("Матч ID=%d помечен как обработанный\n", matchID)
+}
+
+func (c *MemoryCache) IsMatchProcessed(ctx context.Context, leagueID int, season string, matchID int) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.processed[models.Season{LeagueID: leagueID, Season: season}][matchID], nil
+}
+
+func (c *MemoryCache) SetProcessedMatches(ctx context.Context, leagueID int, season string, matchIDs []int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	processed := make(map[int]bool, len(matchIDs))
+	for _, id := range matchIDs {
+		processed[id] = true
+	}
+	c.processed[models.Season{LeagueID: leagueID, Season: season}] = processed
+	return nil
+}
+
+func (c *MemoryCache) ClearSeason(ctx context.Context, leagueID int, season string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := models.Season{LeagueID: leagueID, Season: season}
+	delete(c.seasons, key)
+	delete(c.processed, key)
+	return nil
+}
+
+func (c *MemoryCache) AcquireLease(ctx context.Context, leagueID int, season, owner string, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := models.Season{LeagueID: leagueID, Season: season}
+	if lease, ok := c.leases[key]; ok && time.Now().Before(lease.expires) {
+		return false, nil
+	}
+	c.leases[key] = memoryLease{owner: owner, expires: time.Now().Add(ttl)}
+	return true, nil
+}
+
+func (c *MemoryCache) RenewLease(ctx context.Context, leagueID int, season, owner string, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := models.Season{LeagueID: leagueID, Season: season}
+	lease, ok := c.leases[key]
+	if !ok || lease.owner != owner || !time.Now().Before(lease.expires) {
+		return false, nil
+	}
+	c.leases[key] = memoryLease{owner: owner, expires: time.Now().Add(ttl)}
+	return true, nil
+}
+
+func (c *MemoryCache) ReleaseLease(ctx context.Context, leagueID int, season, owner string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := models.Season{LeagueID: leagueID, Season: season}
+	if lease, ok := c.leases[key]; ok && lease.owner == owner {
+		delete(c.leases, key)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"context"
+	"football-data-miner/internal/models"
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheLeases(t *testing.T) {
+	const ttl = time.Minute
+	type step struct {
+		op    string // acquire, renew, release, expire
+		owner string
+		want  bool
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{"свободный сезон", []step{
+			{"acquire", "a", true},
+		}},
+		{"занятый сезон", []step{
+			{"acquire", "a", true},
+			{"acquire", "b", false},
+		}},
+		{"продление владельцем", []step{
+			{"acquire", "a", true},
+			{"renew", "a", true},
+			{"renew", "b", false},
+		}},
+		{"освобождение", []step{
+			{"acquire", "a", true},
+			{"release", "b", true},
+			{"acquire", "b", false},
+			{"renew", "a", true},
+			{"acquire", "c", false},
+			{"release", "a", true},
+			{"acquire", "c", true},
+			{"renew", "a", false},
+		}},
+		{"истекшая аренда", []step{
+			{"acquire", "a", true},
+			{"expire", "", true},
+			{"renew", "a", false},
+			{"acquire", "b", true},
+			{"renew", "b", true},
+		}},
+		{"продление без аренды", []step{
+			{"renew", "a", false},
+		}},
+	}
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewMemoryCache()
+			for i, s := range tt.steps {
+				var got bool
+				var err error
+				switch s.op {
+				case "acquire":
+					got, err = c.AcquireLease(ctx, 39, "2023", s.owner, ttl)
+				case "renew":
+					got, err = c.RenewLease(ctx, 39, "2023", s.owner, ttl)
+				case "release":
+					err = c.ReleaseLease(ctx, 39, "2023", s.owner)
+					got = err == nil
+				case "expire":
+					key := models.Season{LeagueID: 39, Season: "2023"}
+					lease := c.leases[key]
+					lease.expires = time.Now().Add(-time.Second)
+					c.leases[key] = lease
+					got = true
+				}
+				if err != nil {
+					t.Fatalf("шаг %d (%s %s): %v", i, s.op, s.owner, err)
+				}
+				if got != s.want {
+					t.Fatalf("шаг %d (%s %s) = %t, ожидалось %t", i, s.op, s.owner, got, s.want)
+				}
+			}
+		})
+	}
+
+	t.Run("аренды сезонов независимы", func(t *testing.T) {
+		c := NewMemoryCache()
+		for _, season := range []string{"2022", "2023"} {
+			if ok, _ := c.AcquireLease(ctx, 39, season, "a", ttl); !ok {
+				t.Fatalf("сезон %s не арендован", season)
+			}
+		}
+		if ok, _ := c.AcquireLease(ctx, 140, "2023", "b", ttl); !ok {
+			t.Fatalf("сезон другой лиги не арендован")
+		}
+	})
+}
+
+func TestMemoryCacheProcessed(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache()
+	c.MarkMatchAsProcessed(ctx, 39, "2023", 1)
+	if err := c.SetProcessedMatches(ctx, 39, "2022", []int{10, 11}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		season  string
+		matchID int
+		want    bool
+	}{
+		{"отмеченный матч", "2023", 1, true},
+		{"не отмеченный матч", "2023", 2, false},
+		{"матч другого сезона", "2022", 1, false},
+		{"восстановленное множество", "2022", 11, true},
+	}
+	for _, tt := range tests {
+		got, err := c.IsMatchProcessed(ctx, 39, tt.season, tt.matchID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: IsMatchProcessed = %t, ожидалось %t", tt.name, got, tt.want)
+		}
+	}
+
+	if err := c.ClearSeason(ctx, 39, "2023"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := c.IsMatchProcessed(ctx, 39, "2023", 1); got {
+		t.Errorf("после ClearSeason матч остался обработанным")
+	}
+}
@@ -2,34 +2,98 @@ package cache
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"football-data-miner/internal/models"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 )
 
-var Rdb *redis.Client
+// Сколько хранятся матчи сезона и множество обработанных.
+const seasonTTL = 7 * 24 * time.Hour
 
-func InitRedis() {
-	Rdb = redis.NewClient(&redis.Options{
-		Addr: "localhost:6379",
-	})
-	_, err := Rdb.Ping(context.Background()).Result()
-	if err != nil {
-		panic(err)
+// Сколько ключей Redis просматривает за один шаг SCAN.
+const scanCount = 100
+
+// RedisCache — кэш в Redis: матчи сезона лежат JSON-строкой под GetSeasonKey,
+// обработанные матчи — множеством под GetProcessedKey.
+type RedisCache struct {
+	client *redis.Client
+}
+
+// RedisConfigFromEnv читает подключение из REDIS_URL (redis:// или rediss:// с TLS) либо
+// из REDIS_ADDR, REDIS_USERNAME, REDIS_PASSWORD, REDIS_DB и REDIS_TLS. Переменные можно
+// задать в .env вместе с настройками БД. По умолчанию — localhost:6379 без пароля.
+func RedisConfigFromEnv() (*redis.Options, error) {
+	if url := os.Getenv("REDIS_URL"); url != "" {
+		options, err := redis.ParseURL(url)
+		if err != nil {
+			return nil, fmt.Errorf("некорректный REDIS_URL: %v", err)
+		}
+		return options, nil
+	}
+
+	options := &redis.Options{
+		Addr:     os.Getenv("REDIS_ADDR"),
+		Username: os.Getenv("REDIS_USERNAME"),
+		Password: os.Getenv("REDIS_PASSWORD"),
+	}
+	if options.Addr == "" {
+		options.Addr = "localhost:6379"
+	}
+	if db := os.Getenv("REDIS_DB"); db != "" {
+		index, err := strconv.Atoi(db)
+		if err != nil {
+			return nil, fmt.Errorf("некорректный REDIS_DB: %v", err)
+		}
+		options.DB = index
+	}
+	if useTLS, _ := strconv.ParseBool(os.Getenv("REDIS_TLS")); useTLS {
+		host := options.Addr
+		if i := strings.LastIndex(host, ":"); i >= 0 {
+			host = host[:i]
+		}
+		options.TLSConfig = &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
 	}
+	return options, nil
 }
 
-func IsCacheEmpty() bool {
-	keys, _ := Rdb.Keys(context.Background(), "matches:season:*").Result()
-	return len(keys) == 0
+// NewRedisCache подключается к Redis и проверяет соединение.
+func NewRedisCache(ctx context.Context, options *redis.Options) (*RedisCache, error) {
+	client := redis.NewClient(options)
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("ошибка подключения к Redis %s: %v", options.Addr, err)
+	}
+	return &RedisCache{client: client}, nil
+}
+
+func (c *RedisCache) Close() error {
+	return c.client.Close()
+}
+
+func (c *RedisCache) CachedSeasons(ctx context.Context) ([]models.Season, error) {
+	keys, err := c.scanKeys(ctx, "matches:season:*")
+	if err != nil {
+		return nil, err
+	}
+	var seasons []models.Season
+	for _, key := range keys {
+		if season, ok := parseSeasonKey(key); ok {
+			seasons = append(seasons, season)
+		}
+	}
+	return seasons, nil
 }
 
-func GetSeasonMatches(leagueID int, season string) ([]models.Match, error) {
+func (c *RedisCache) GetSeasonMatches(ctx context.Context, leagueID int, season string) ([]models.Match, error) {
 	key := GetSeasonKey(leagueID, season)
-	matchesJSON, err := Rdb.Get(context.Background(), key).Result()
+	matchesJSON, err := c.client.Get(ctx, key).Result()
 	if err != nil {
 		return nil, fmt.Errorf("ошибка при получении матчей: %v", err)
 	}
@@ -43,25 +107,25 @@ func GetSeasonMatches(leagueID int, season string) ([]models.Match, error) {
 	return matches, nil
 }
 
-func CacheSeasonMatches(leagueID int, season string, matches []models.Match) error {
+// CacheSeasonMatches сохраняет матчи сезона.
+func (c *RedisCache) CacheSeasonMatches(ctx context.Context, leagueID int, season string, matches []models.Match) error {
 	key := GetSeasonKey(leagueID, season)
 	matchesJSON, err := json.Marshal(matches)
 	if err != nil {
 		return fmt.Errorf("ошибка сериализации: %v", err)
 	}
 
-	err = Rdb.Set(context.Background(), key, string(matchesJSON), 7*24*time.Hour).Err()
-	if err != nil {
+	if err := c.client.Set(ctx, key, string(matchesJSON), seasonTTL).Err(); err != nil {
 		return fmt.Errorf("ошибка сохранения в Redis: %v", err)
 	}
 
 	return nil
 }
 
-func MarkMatchAsProcessed(leagueID int, season string, matchID int) {
+func (c *RedisCache) MarkMatchAsProcessed(ctx context.Context, leagueID int, season string, matchID int) {
 	processedKey := GetProcessedKey(leagueID, season)
 
-	err := Rdb.SAdd(context.Background(), processedKey, matchID).Err()
+	err := c.client.SAdd(ctx, processedKey, matchID).Err()
 	if err != nil {
 		fmt.Printf("Ошибка при добавлении матча ID=%d в множество обработанных: %v\n", matchID, err)
 		return
@@ -69,65 +133,113 @@ func MarkMatchAsProcessed(leagueID int, season string, matchID int) {
 
 	fmt.Printf("Матч ID=%d помечен как обработанный\n", matchID)
 
-	err = Rdb.Expire(context.Background(), processedKey, 7*24*time.Hour).Err()
+	err = c.client.Expire(ctx, processedKey, seasonTTL).Err()
 	if err != nil {
 		fmt.Printf("Ошибка при установке TTL для ключа %s: %v\n", processedKey, err)
 	}
 }
 
-func IsMatchProcessed(leagueID int, season string, matchID int) (bool, error) {
+func (c *RedisCache) IsMatchProcessed(ctx context.Context, leagueID int, season string, matchID int) (bool, error) {
 	processedKey := GetProcessedKey(leagueID, season)
-	isProcessed, err := Rdb.SIsMember(context.Background(), processedKey, matchID).Result()
+	isProcessed, err := c.client.SIsMember(ctx, processedKey, matchID).Result()
 	return isProcessed, err
 }
 
-func GetAllSeasonKeys() ([]string, error) {
-	return Rdb.Keys(context.Background(), "matches:season:*").Result()
+// SetProcessedMatches заменяет множество обработанных матчей сезона.
+func (c *RedisCache) SetProcessedMatches(ctx context.Context, leagueID int, season string, matchIDs []int) error {
+	processedKey := GetProcessedKey(leagueID, season)
+	members := make([]interface{}, len(matchIDs))
+	for i, id := range matchIDs {
+		members[i] = id
+	}
+
+	pipe := c.client.TxPipeline()
+	pipe.Del(ctx, processedKey)
+	if len(members) > 0 {
+		pipe.SAdd(ctx, processedKey, members...)
+		pipe.Expire(ctx, processedKey, seasonTTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("ошибка сохранения в Redis: %v", err)
+	}
+	return nil
 }
 
-func GetMatchFromRedis(fixtureID int) (*models.Match, error) {
-	keys, err := Rdb.Keys(context.Background(), "matches:season:*").Result()
+// ClearSeason удаляет матчи сезона и множество его обработанных матчей.
+func (c *RedisCache) ClearSeason(ctx context.Context, leagueID int, season string) error {
+	return c.client.Del(ctx, GetSeasonKey(leagueID, season), GetProcessedKey(leagueID, season)).Err()
+}
+
+// Продление и снятие аренды только владельцем: проверка и изменение атомарны.
+var (
+	renewLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+    return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+	releaseLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+    return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// AcquireLease берет аренду сезона через SET NX с истечением.
+func (c *RedisCache) AcquireLease(ctx context.Context, leagueID int, season, owner string, ttl time.Duration) (bool, error) {
+	ok, err := c.client.SetNX(ctx, GetLeaseKey(leagueID, season), owner, ttl).Result()
 	if err != nil {
-		return nil, fmt.Errorf("ошибка при поиске матчей: %v", err)
+		return false, fmt.Errorf("ошибка аренды сезона: %v", err)
 	}
+	return ok, nil
+}
 
-	for _, key := range keys {
-		matchesJSON, err := Rdb.Get(context.Background(), key).Result()
-		if err != nil {
-			continue
-		}
-
-		var matches []models.Match
-		err = json.Unmarshal([]byte(matchesJSON), &matches)
-		if err != nil {
-			continue
-		}
+// RenewLease продлевает аренду; false — аренда истекла или перешла к другому процессу.
+func (c *RedisCache) RenewLease(ctx context.Context, leagueID int, season, owner string, ttl time.Duration) (bool, error) {
+	renewed, err := renewLeaseScript.Run(ctx, c.client, []string{GetLeaseKey(leagueID, season)}, owner, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("ошибка продления аренды сезона: %v", err)
+	}
+	return renewed == 1, nil
+}
 
-		for _, match := range matches {
-			if match.ID == fixtureID {
-				return &match, nil
-			}
-		}
+func (c *RedisCache) ReleaseLease(ctx context.Context, leagueID int, season, owner string) error {
+	err := releaseLeaseScript.Run(ctx, c.client, []string{GetLeaseKey(leagueID, season)}, owner).Err()
+	if err != nil && err != redis.Nil {
+		return fmt.Errorf("ошибка снятия аренды сезона: %v", err)
 	}
+	return nil
+}
 
-	return nil, fmt.Errorf("матч ID=%d не найден", fixtureID)
+// scanKeys перебирает ключи по шаблону через SCAN, не блокируя Redis, в отличие от KEYS.
+func (c *RedisCache) scanKeys(ctx context.Context, pattern string) ([]string, error) {
+	var keys []string
+	iter := c.client.Scan(ctx, 0, pattern, scanCount).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	return keys, iter.Err()
 }
 
 func GetSeasonKey(leagueID int, season string) string {
 	return fmt.Sprintf("matches:season:%d:%s", leagueID, season)
 }
 
+func GetLeaseKey(leagueID int, season string) string {
+	return fmt.Sprintf("lease:season:%d:%s", leagueID, season)
+}
+
 func GetProcessedKey(leagueID int, season string) string {
 	return fmt.Sprintf("processed_matches:season:%d:%s", leagueID, season)
 }
 
-func IsSeasonCompleted(leagueID int, season string, totalMatches int) (bool, error) {
-	processedKey := GetProcessedKey(leagueID, season)
-	processedCount, err := Rdb.SCard(context.Background(), processedKey).Result()
+func parseSeasonKey(key string) (models.Season, bool) {
+	parts := strings.Split(key, ":")
+	if len(parts) != 4 {
+		return models.Season{}, false
+	}
+	leagueID, err := strconv.Atoi(parts[2])
 	if err != nil {
-		return false, fmt.Errorf("ошибка проверки обработанных матчей: %v", err)
+		return models.Season{}, false
 	}
-
-	fmt.Printf("Обработано матчей: %d из %d\n", processedCount, totalMatches)
-	return processedCount == int64(totalMatches), nil
+	return models.Season{LeagueID: leagueID, Season: parts[3]}, true
 }
@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"football-data-miner/internal/models"
+)
+
+// ListLeagueSeasons возвращает очередь сезонов в порядке обработки; leagueID = 0 — все лиги.
+func ListLeagueSeasons(ctx context.Context, leagueID int) ([]models.LeagueSeason, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := DB.QueryContext(ctx, `
+        SELECT league_id, season, league_name, country,
+               to_char(start_date, 'YYYY-MM-DD'), to_char(end_date, 'YYYY-MM-DD'),
+               priority, is_processed,
+               coverage_events, coverage_lineups, coverage_statistics, coverage_players,
+               coverage_standings, coverage_injuries, coverage_odds
+        FROM league_seasons
+        WHERE $1 = 0 OR league_id = $1
+        ORDER BY is_processed, priority DESC, season, league_id
+    `, leagueID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса сезонов: %v", err)
+	}
+	defer rows.Close()
+
+	var seasons []models.LeagueSeason
+	for rows.Next() {
+		var s models.LeagueSeason
+		err := rows.Scan(&s.LeagueID, &s.Season, &s.LeagueName, &s.Country,
+			&s.StartDate, &s.EndDate,
+			&s.Priority, &s.IsProcessed,
+			&s.Coverage.Events, &s.Coverage.Lineups, &s.Coverage.Statistics, &s.Coverage.Players,
+			&s.Coverage.Standings, &s.Coverage.Injuries, &s.Coverage.Odds)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка сканирования сезона: %v", err)
+		}
+		seasons = append(seasons, s)
+	}
+	return seasons, rows.Err()
+}
+
+// SaveLeagueSeason добавляет сезон в очередь или обновляет его описание. Приоритет
+// существующего сезона меняется только при setPriority. Флаг is_processed существующего
+// сезона не меняется — для этого есть ResetLeagueSeason.
+func SaveLeagueSeason(ctx context.Context, s models.LeagueSeason, setPriority bool) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	_, err := DB.ExecContext(ctx, `
+        INSERT INTO league_seasons (
+            league_id, season, is_processed, priority, league_name, country, start_date, end_date,
+            coverage_events, coverage_lineups, coverage_statistics, coverage_players,
+            coverage_standings, coverage_injuries, coverage_odds
+        ) VALUES ($1, $2, FALSE, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+        ON CONFLICT (league_id, season) DO UPDATE SET
+            priority = CASE WHEN $15 THEN EXCLUDED.priority ELSE league_seasons.priority END,
+            league_name = COALESCE(NULLIF(EXCLUDED.league_name, ''), league_seasons.league_name),
+            country = COALESCE(NULLIF(EXCLUDED.country, ''), league_seasons.country),
+            start_date = COALESCE(EXCLUDED.start_date, league_seasons.start_date),
+            end_date = COALESCE(EXCLUDED.end_date, league_seasons.end_date),
+            coverage_events = EXCLUDED.coverage_events OR league_seasons.coverage_events,
+            coverage_lineups = EXCLUDED.coverage_lineups OR league_seasons.coverage_lineups,
+            coverage_statistics = EXCLUDED.coverage_statistics OR league_seasons.coverage_statistics,
+            coverage_players = EXCLUDED.coverage_players OR league_seasons.coverage_players,
+            coverage_standings = EXCLUDED.coverage_standings OR league_seasons.coverage_standings,
+            coverage_injuries = EXCLUDED.coverage_injuries OR league_seasons.coverage_injuries,
+            coverage_odds = EXCLUDED.coverage_odds OR league_seasons.coverage_odds
+    `, s.LeagueID, s.Season, s.Priority, s.LeagueName, s.Country, s.StartDate, s.EndDate,
+		s.Coverage.Events, s.Coverage.Lineups, s.Coverage.Statistics, s.Coverage.Players,
+		s.Coverage.Standings, s.Coverage.Injuries, s.Coverage.Odds, setPriority)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения сезона лиги %d, сезон %s: %v", s.LeagueID, s.Season, err)
+	}
+	return nil
+}
+
+// RemoveLeagueSeason удаляет сезон из очереди. Уже сохраненные матчи не затрагиваются.
+func RemoveLeagueSeason(ctx context.Context, leagueID int, season string) (bool, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	result, err := DB.ExecContext(ctx, `
+        DELETE FROM league_seasons
+        WHERE league_id = $1 AND season = $2
+    `, leagueID, season)
+	if err != nil {
+		return false, fmt.Errorf("ошибка удаления сезона лиги %d, сезон %s: %v", leagueID, season, err)
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
+
+// ResetLeagueSeason снова открывает сезон для обработки.
+func ResetLeagueSeason(ctx context.Context, leagueID int, season string) (bool, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	result, err := DB.ExecContext(ctx, `
+        UPDATE league_seasons
+        SET is_processed = FALSE
+        WHERE league_id = $1 AND season = $2
+    `, leagueID, season)
+	if err != nil {
+		return false, fmt.Errorf("ошибка сброса сезона лиги %d, сезон %s: %v", leagueID, season, err)
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
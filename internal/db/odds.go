@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"football-data-miner/internal/models"
+	"time"
+)
+
+// SaveOdds сохраняет снимок коэффициентов матча с общим временем фиксации.
+func SaveOdds(ctx context.Context, odds []models.Odd) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %v", err)
+	}
+	defer tx.Rollback()
+
+	capturedAt := time.Now()
+	query := `
+        INSERT INTO odds (
+            fixture_id, bookmaker_id, bookmaker, market, selection, value, api_updated_at, captured_at
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        ON CONFLICT (fixture_id, bookmaker_id, market, selection, captured_at) DO NOTHING
+    `
+	for _, odd := range odds {
+		_, err := tx.ExecContext(ctx, query,
+			odd.FixtureID,
+			odd.BookmakerID,
+			odd.Bookmaker,
+			odd.Market,
+			odd.Selection,
+			odd.Value,
+			odd.APIUpdatedAt,
+			capturedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("ошибка сохранения коэффициента матча ID=%d: %v", odd.FixtureID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка коммита транзакции: %v", err)
+	}
+	return nil
+}
+
+// GetLastOddsCapture возвращает время последнего снимка коэффициентов матча или nil.
+func GetLastOddsCapture(ctx context.Context, fixtureID int) (*time.Time, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	var capturedAt sql.NullTime
+	err := DB.QueryRowContext(ctx, `
+        SELECT MAX(captured_at)
+        FROM odds
+        WHERE fixture_id = $1
+    `, fixtureID).Scan(&capturedAt)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения снимка коэффициентов матча ID=%d: %v", fixtureID, err)
+	}
+	if !capturedAt.Valid {
+		return nil, nil
+	}
+	return &capturedAt.Time, nil
+}
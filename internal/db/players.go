@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"football-data-miner/internal/models"
+	"time"
+)
+
+// SavePlayerProfile сохраняет или обновляет профиль игрока из /players
+// и отмечает время обновления и проверки.
+func SavePlayerProfile(ctx context.Context, player models.Player) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	_, err := DB.ExecContext(ctx, `
+        INSERT INTO players (
+            id, fullname, firstname, lastname, birth_date, birth_place, birth_country,
+            nationality, height, weight, photo, profile_updated_at, profile_checked_at
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW(), NOW())
+        ON CONFLICT (id) DO UPDATE SET
+            fullname = EXCLUDED.fullname, firstname = EXCLUDED.firstname,
+            lastname = EXCLUDED.lastname, birth_date = EXCLUDED.birth_date,
+            birth_place = EXCLUDED.birth_place, birth_country = EXCLUDED.birth_country,
+            nationality = EXCLUDED.nationality, height = EXCLUDED.height,
+            weight = EXCLUDED.weight, photo = EXCLUDED.photo,
+            profile_updated_at = EXCLUDED.profile_updated_at,
+            profile_checked_at = EXCLUDED.profile_checked_at
+    `, player.ID, player.Fullname, player.Firstname, player.Lastname, player.BirthDate,
+		player.BirthPlace, player.BirthCountry, player.Nationality, player.Height,
+		player.Weight, player.Photo)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения профиля игрока ID=%d: %v", player.ID, err)
+	}
+	return nil
+}
+
+// MarkSeasonPlayersChecked отмечает проверенными всех игроков сезона после полного
+// прохода /players, в том числе тех, кого API не вернул.
+func MarkSeasonPlayersChecked(ctx context.Context, leagueID int, season string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	_, err := DB.ExecContext(ctx, `
+        UPDATE players p SET profile_checked_at = NOW()
+        WHERE p.id IN (
+            SELECT l.player_id
+            FROM lineups l
+            JOIN matches m ON m.id = l.match_id
+            WHERE m.league_id = $1 AND m.season = $2
+        )
+    `, leagueID, season)
+	if err != nil {
+		return fmt.Errorf("ошибка отметки проверенных профилей лиги %d, сезон %s: %v", leagueID, season, err)
+	}
+	return nil
+}
+
+// CountStalePlayers считает игроков сезона, которых не проверяли в /players
+// с момента staleBefore.
+func CountStalePlayers(ctx context.Context, leagueID int, season string, staleBefore time.Time) (int, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	var count int
+	err := DB.QueryRowContext(ctx, `
+        SELECT COUNT(DISTINCT p.id)
+        FROM players p
+        JOIN lineups l ON l.player_id = p.id
+        JOIN matches m ON m.id = l.match_id
+        WHERE m.league_id = $1 AND m.season = $2
+          AND (p.profile_checked_at IS NULL OR p.profile_checked_at < $3)
+    `, leagueID, season, staleBefore).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка подсчета устаревших профилей лиги %d, сезон %s: %v", leagueID, season, err)
+	}
+	return count, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"football-data-miner/internal/models"
 
@@ -15,11 +16,24 @@ import (
 
 var DB *sql.DB
 
-func InitDB() {
+// queryTimeout ограничивает один запрос или транзакцию (DB_TIMEOUT, например "30s").
+var queryTimeout = 30 * time.Second
+
+func withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, queryTimeout)
+}
+
+func InitDB(ctx context.Context) {
 	err := godotenv.Load()
 	if err != nil {
 		log.Fatalf("Ошибка загрузки .env файла: %v", err)
 	}
+	if timeout := os.Getenv("DB_TIMEOUT"); timeout != "" {
+		queryTimeout, err = time.ParseDuration(timeout)
+		if err != nil {
+			log.Fatalf("Некорректный DB_TIMEOUT: %v", err)
+		}
+	}
 	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		os.Getenv("POSTGRES_HOST"),
 		os.Getenv("POSTGRES_PORT"),
@@ -31,7 +45,7 @@ func InitDB() {
 		log.Fatalf("Ошибка подключения к БД: %v", err)
 	}
 
-	err = DB.Ping()
+	err = DB.PingContext(ctx)
 	if err != nil {
 		log.Fatalf("Не удалось подключиться к БД: %v", err)
 	}
@@ -47,44 +61,73 @@ func CloseDB() {
 		}
 	}
 }
-func GetNextUnprocessedSeason() (int, string) {
-	var leagueID int
-	var season string
-	err := DB.QueryRowContext(context.Background(), `
+
+// GetUnprocessedSeasons возвращает необработанные сезоны в порядке очереди.
+func GetUnprocessedSeasons(ctx context.Context) ([]models.Season, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := DB.QueryContext(ctx, `
         SELECT league_id, season
         FROM league_seasons
         WHERE is_processed = FALSE
-        ORDER BY season ASC
-        LIMIT 1
-    `).Scan(&leagueID, &season)
+        ORDER BY priority DESC, season ASC
+    `)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса необработанных сезонов: %v", err)
+	}
+	defer rows.Close()
 
-	if err == sql.ErrNoRows {
-		return 0, ""
+	var seasons []models.Season
+	for rows.Next() {
+		var season models.Season
+		if err := rows.Scan(&season.LeagueID, &season.Season); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования сезона: %v", err)
+		}
+		seasons = append(seasons, season)
 	}
+	return seasons, rows.Err()
+}
+
+// IsSeasonProcessed проверяет, отмечен ли сезон обработанным (например, другим процессом).
+func IsSeasonProcessed(ctx context.Context, leagueID int, season string) (bool, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	var processed bool
+	err := DB.QueryRowContext(ctx, `
+        SELECT COALESCE(bool_and(is_processed), FALSE)
+        FROM league_seasons
+        WHERE league_id = $1 AND season = $2
+    `, leagueID, season).Scan(&processed)
 	if err != nil {
-		panic(err)
+		return false, fmt.Errorf("ошибка проверки сезона лиги %d, сезон %s: %v", leagueID, season, err)
 	}
-
-	return leagueID, season
+	return processed, nil
 }
 
-func MarkSeasonAsProcessed(leagueID int, season string) error {
+func MarkSeasonAsProcessed(ctx context.Context, leagueID int, season string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
 	query := `
         UPDATE league_seasons
         SET is_processed = TRUE
         WHERE league_id = $1 AND season = $2
     `
-	_, err := DB.Exec(query, leagueID, season)
-	return err
+	_, err := DB.ExecContext(ctx, query, leagueID, season)
+	if err != nil {
+		return fmt.Errorf("ошибка отметки сезона лиги %d, сезон %s обработанным: %v", leagueID, season, err)
+	}
+	return nil
 }
 
-func GetProcessedSeasons() ([]models.Season, error) {
+func GetProcessedSeasons(ctx context.Context) ([]models.Season, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
 	query := `
         SELECT league_id, season
         FROM league_seasons
         WHERE is_processed = TRUE
     `
-	rows, err := DB.Query(query)
+	rows, err := DB.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка запроса обработанных сезонов: %v", err)
 	}
@@ -102,7 +145,9 @@ func GetProcessedSeasons() ([]models.Season, error) {
 
 	return seasons, nil
 }
-func IsMatchExists(matchID int) (bool, error) {
+func IsMatchExists(ctx context.Context, matchID int) (bool, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
 	query := `
         SELECT EXISTS (
             SELECT 1
@@ -111,21 +156,23 @@ func IsMatchExists(matchID int) (bool, error) {
         )
     `
 	var exists bool
-	err := DB.QueryRow(query, matchID).Scan(&exists)
+	err := DB.QueryRowContext(ctx, query, matchID).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("ошибка проверки существования матча ID=%d: %v", matchID, err)
 	}
 	return exists, nil
 }
 
-func GetSeasonMatches(leagueID int, seasonDate string) ([]models.Match, error) {
+func GetSeasonMatches(ctx context.Context, leagueID int, seasonDate string) ([]models.Match, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
 	query := `
         SELECT id, date, home_team_id, away_team_id, home_score, away_score 
         FROM matches 
         WHERE league_id = $1 AND date <= $2 
         ORDER BY date ASC
     `
-	rows, err := DB.Query(query, leagueID, seasonDate)
+	rows, err := DB.QueryContext(ctx, query, leagueID, seasonDate)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка получения матчей сезона: %v", err)
 	}
@@ -143,7 +190,9 @@ func GetSeasonMatches(leagueID int, seasonDate string) ([]models.Match, error) {
 
 	return matches, nil
 }
-func GetMissingMatchesFromDB(db *sql.DB) ([]models.Match, error) {
+func GetMissingMatchesFromDB(ctx context.Context, db *sql.DB) ([]models.Match, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
 	query := `
         SELECT id, date, league_id, season, home_team_id, away_team_id, home_score, away_score
         FROM matches
@@ -151,7 +200,7 @@ func GetMissingMatchesFromDB(db *sql.DB) ([]models.Match, error) {
           AND league_id IN (39, 78, 135, 140, 61)
           AND id NOT IN (SELECT match_id FROM match_statistics)
     `
-	rows, err := db.Query(query)
+	rows, err := db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка выполнения запроса: %v", err)
 	}
@@ -181,7 +230,9 @@ func GetMissingMatchesFromDB(db *sql.DB) ([]models.Match, error) {
 	return matches, nil
 }
 
-func GetLeagueAndSeasonForMatch(matchID int) (int, string, error) {
+func GetLeagueAndSeasonForMatch(ctx context.Context, matchID int) (int, string, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
 	query := `
         SELECT league_id, season
         FROM matches
@@ -189,20 +240,22 @@ func GetLeagueAndSeasonForMatch(matchID int) (int, string, error) {
     `
 	var leagueID int
 	var season string
-	err := DB.QueryRow(query, matchID).Scan(&leagueID, &season)
+	err := DB.QueryRowContext(ctx, query, matchID).Scan(&leagueID, &season)
 	if err != nil {
 		return 0, "", fmt.Errorf("ошибка получения лиги и сезона для матча ID=%d: %v", matchID, err)
 	}
 	return leagueID, season, nil
 }
 
-func GetProcessedMatches(leagueID int, season string) ([]int, error) {
+func GetProcessedMatches(ctx context.Context, leagueID int, season string) ([]int, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
 	query := `
         SELECT id 
         FROM matches
         WHERE league_id=$1 and season=$2
     `
-	rows, err := DB.Query(query, leagueID, season)
+	rows, err := DB.QueryContext(ctx, query, leagueID, season)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка выполнения запроса: %v", err)
 	}
@@ -0,0 +1,258 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"football-data-miner/internal/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// AddPendingMatches регистрирует матчи сезона в ingestion_progress. Уже известные не меняются.
+func AddPendingMatches(ctx context.Context, leagueID int, season string, matchIDs []int) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	ids := make([]int64, len(matchIDs))
+	for i, id := range matchIDs {
+		ids[i] = int64(id)
+	}
+	_, err := DB.ExecContext(ctx, `
+        INSERT INTO ingestion_progress (match_id, league_id, season, state)
+        SELECT id, $2, $3, 'pending'
+        FROM unnest($1::INTEGER[]) AS id
+        ON CONFLICT (match_id) DO NOTHING
+    `, pq.Array(ids), leagueID, season)
+	if err != nil {
+		return fmt.Errorf("ошибка регистрации матчей лиги %d, сезон %s: %v", leagueID, season, err)
+	}
+	return nil
+}
+
+// GetSeasonProgress возвращает состояние загрузки матчей сезона по ID матча.
+func GetSeasonProgress(ctx context.Context, leagueID int, season string) (map[int]models.IngestionProgress, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := DB.QueryContext(ctx, `
+        SELECT match_id, league_id, season, state, attempts, last_error, next_attempt_at, updated_at
+        FROM ingestion_progress
+        WHERE league_id = $1 AND season = $2
+    `, leagueID, season)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса прогресса лиги %d, сезон %s: %v", leagueID, season, err)
+	}
+	defer rows.Close()
+
+	progress := make(map[int]models.IngestionProgress)
+	for rows.Next() {
+		var p models.IngestionProgress
+		if err := scanProgress(rows, &p); err != nil {
+			return nil, err
+		}
+		progress[p.MatchID] = p
+	}
+	return progress, rows.Err()
+}
+
+// StartMatchAttempt отмечает начало очередной попытки загрузить матч и возвращает номер попытки.
+func StartMatchAttempt(ctx context.Context, leagueID int, season string, matchID int) (int, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	var attempts int
+	err := DB.QueryRowContext(ctx, `
+        INSERT INTO ingestion_progress (match_id, league_id, season, state, attempts)
+        VALUES ($1, $2, $3, 'pending', 1)
+        ON CONFLICT (match_id) DO UPDATE SET
+            attempts = ingestion_progress.attempts + 1, updated_at = now()
+        RETURNING attempts
+    `, matchID, leagueID, season).Scan(&attempts)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка обновления прогресса матча ID=%d: %v", matchID, err)
+	}
+	return attempts, nil
+}
+
+// SetMatchState записывает состояние загрузки матча. lastError сохраняется только для failed,
+// в остальных состояниях очищается. Для неудач с повтором — FailMatch.
+func SetMatchState(ctx context.Context, leagueID int, season string, matchID int, state, lastError string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	if state != models.ProgressFailed {
+		lastError = ""
+	}
+	_, err := DB.ExecContext(ctx, `
+        INSERT INTO ingestion_progress (match_id, league_id, season, state, last_error)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (match_id) DO UPDATE SET
+            state = EXCLUDED.state, last_error = EXCLUDED.last_error,
+            next_attempt_at = NULL, updated_at = now()
+    `, matchID, leagueID, season, state, lastError)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления прогресса матча ID=%d: %v", matchID, err)
+	}
+	return nil
+}
+
+// GetDoneMatches возвращает ID матчей сезона в конечных состояниях.
+func GetDoneMatches(ctx context.Context, leagueID int, season string) ([]int, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := DB.QueryContext(ctx, `
+        SELECT match_id
+        FROM ingestion_progress
+        WHERE league_id = $1 AND season = $2 AND state IN ('saved', 'skipped', 'abandoned')
+    `, leagueID, season)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса прогресса лиги %d, сезон %s: %v", leagueID, season, err)
+	}
+	defer rows.Close()
+
+	var matchIDs []int
+	for rows.Next() {
+		var matchID int
+		if err := rows.Scan(&matchID); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования ID матча: %v", err)
+		}
+		matchIDs = append(matchIDs, matchID)
+	}
+	return matchIDs, rows.Err()
+}
+
+func markMatchSaved(ctx context.Context, tx *sql.Tx, leagueID int, season string, matchID int) error {
+	_, err := tx.ExecContext(ctx, `
+        INSERT INTO ingestion_progress (match_id, league_id, season, state)
+        VALUES ($1, $2, $3, 'saved')
+        ON CONFLICT (match_id) DO UPDATE SET
+            state = 'saved', last_error = '', next_attempt_at = NULL, updated_at = now()
+    `, matchID, leagueID, season)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления прогресса: %v", err)
+	}
+	return nil
+}
+
+// FailMatch записывает неудачную попытку. С retryAt матч остается failed и повторяется
+// не раньше этого времени, без него — уходит в parked до решения оператора.
+func FailMatch(ctx context.Context, leagueID int, season string, matchID int, lastError string, retryAt *time.Time) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	state := models.ProgressFailed
+	if retryAt == nil {
+		state = models.ProgressParked
+	}
+	_, err := DB.ExecContext(ctx, `
+        INSERT INTO ingestion_progress (match_id, league_id, season, state, last_error, next_attempt_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (match_id) DO UPDATE SET
+            state = EXCLUDED.state, last_error = EXCLUDED.last_error,
+            next_attempt_at = EXCLUDED.next_attempt_at, updated_at = now()
+    `, matchID, leagueID, season, state, lastError, retryAt)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления прогресса матча ID=%d: %v", matchID, err)
+	}
+	return nil
+}
+
+// ListParkedMatches возвращает отложенные до решения оператора матчи; leagueID = 0 — все лиги.
+func ListParkedMatches(ctx context.Context, leagueID int) ([]models.IngestionProgress, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := DB.QueryContext(ctx, `
+        SELECT match_id, league_id, season, state, attempts, last_error, next_attempt_at, updated_at
+        FROM ingestion_progress
+        WHERE state = 'parked' AND ($1 = 0 OR league_id = $1)
+        ORDER BY league_id, season, match_id
+    `, leagueID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса отложенных матчей: %v", err)
+	}
+	defer rows.Close()
+
+	var parked []models.IngestionProgress
+	for rows.Next() {
+		var p models.IngestionProgress
+		if err := scanProgress(rows, &p); err != nil {
+			return nil, err
+		}
+		parked = append(parked, p)
+	}
+	return parked, rows.Err()
+}
+
+// RetryParkedMatches возвращает отложенные матчи в очередь со сброшенным счетчиком попыток
+// и снова открывает их сезоны. Фильтры matchID и leagueID = 0 не ограничивают выборку.
+func RetryParkedMatches(ctx context.Context, matchID, leagueID int) (int, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка начала транзакции: %v", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+        UPDATE ingestion_progress
+        SET state = 'pending', attempts = 0, next_attempt_at = NULL, updated_at = now()
+        WHERE state = 'parked' AND ($1 = 0 OR match_id = $1) AND ($2 = 0 OR league_id = $2)
+        RETURNING league_id, season
+    `, matchID, leagueID)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка возврата матчей в очередь: %v", err)
+	}
+	retried := 0
+	seasons := make(map[models.Season]bool)
+	for rows.Next() {
+		var s models.Season
+		if err := rows.Scan(&s.LeagueID, &s.Season); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("ошибка сканирования сезона: %v", err)
+		}
+		seasons[s] = true
+		retried++
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("ошибка возврата матчей в очередь: %v", err)
+	}
+
+	for s := range seasons {
+		_, err := tx.ExecContext(ctx, `
+            UPDATE league_seasons
+            SET is_processed = FALSE
+            WHERE league_id = $1 AND season = $2
+        `, s.LeagueID, s.Season)
+		if err != nil {
+			return 0, fmt.Errorf("ошибка открытия сезона лиги %d, сезон %s: %v", s.LeagueID, s.Season, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("ошибка коммита транзакции: %v", err)
+	}
+	return retried, nil
+}
+
+// AbandonParkedMatches отказывается от загрузки отложенных матчей: сезон больше их не ждет.
+func AbandonParkedMatches(ctx context.Context, matchID, leagueID int) (int, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	result, err := DB.ExecContext(ctx, `
+        UPDATE ingestion_progress
+        SET state = 'abandoned', next_attempt_at = NULL, updated_at = now()
+        WHERE state = 'parked' AND ($1 = 0 OR match_id = $1) AND ($2 = 0 OR league_id = $2)
+    `, matchID, leagueID)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка отказа от матчей: %v", err)
+	}
+	affected, _ := result.RowsAffected()
+	return int(affected), nil
+}
+
+func scanProgress(rows *sql.Rows, p *models.IngestionProgress) error {
+	err := rows.Scan(&p.MatchID, &p.LeagueID, &p.Season, &p.State, &p.Attempts, &p.LastError, &p.NextAttemptAt, &p.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка сканирования прогресса: %v", err)
+	}
+	return nil
+}
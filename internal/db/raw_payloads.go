@@ -0,0 +1,174 @@
+package db
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+
+	"football-data-miner/internal/models"
+)
+
+// SaveRawPayload сохраняет сжатое gzip тело ответа API. Повторное сохранение заменяет запись.
+func SaveRawPayload(ctx context.Context, endpoint string, fixtureID int, body []byte) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	compressed, err := compressPayload(body)
+	if err != nil {
+		return err
+	}
+
+	_, err = DB.ExecContext(ctx, `
+        INSERT INTO raw_payloads (endpoint, fixture_id, fetched_at, body)
+        VALUES ($1, $2, NOW(), $3)
+        ON CONFLICT (endpoint, fixture_id) DO UPDATE
+        SET fetched_at = EXCLUDED.fetched_at, body = EXCLUDED.body
+    `, endpoint, fixtureID, compressed)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения ответа %s для матча ID=%d: %v", endpoint, fixtureID, err)
+	}
+	return nil
+}
+
+// GetRawPayload возвращает распакованное тело ответа или sql.ErrNoRows, если архива нет.
+func GetRawPayload(ctx context.Context, endpoint string, fixtureID int) ([]byte, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	var compressed []byte
+	err := DB.QueryRowContext(ctx, `
+        SELECT body
+        FROM raw_payloads
+        WHERE endpoint = $1 AND fixture_id = $2
+    `, endpoint, fixtureID).Scan(&compressed)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения ответа %s для матча ID=%d: %v", endpoint, fixtureID, err)
+	}
+	return decompressPayload(compressed)
+}
+
+// SaveSeasonPayload сохраняет сжатое gzip тело ответа API по лиге и сезону.
+// Повторное сохранение заменяет запись.
+func SaveSeasonPayload(ctx context.Context, endpoint string, leagueID int, season string, body []byte) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	compressed, err := compressPayload(body)
+	if err != nil {
+		return err
+	}
+
+	_, err = DB.ExecContext(ctx, `
+        INSERT INTO raw_season_payloads (endpoint, league_id, season, fetched_at, body)
+        VALUES ($1, $2, $3, NOW(), $4)
+        ON CONFLICT (endpoint, league_id, season) DO UPDATE
+        SET fetched_at = EXCLUDED.fetched_at, body = EXCLUDED.body
+    `, endpoint, leagueID, season, compressed)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения ответа %s для лиги %d, сезон %s: %v", endpoint, leagueID, season, err)
+	}
+	return nil
+}
+
+// GetSeasonPayload возвращает распакованное тело ответа по лиге и сезону или sql.ErrNoRows.
+func GetSeasonPayload(ctx context.Context, endpoint string, leagueID int, season string) ([]byte, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	var compressed []byte
+	err := DB.QueryRowContext(ctx, `
+        SELECT body
+        FROM raw_season_payloads
+        WHERE endpoint = $1 AND league_id = $2 AND season = $3
+    `, endpoint, leagueID, season).Scan(&compressed)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения ответа %s для лиги %d, сезон %s: %v", endpoint, leagueID, season, err)
+	}
+	return decompressPayload(compressed)
+}
+
+func compressPayload(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return nil, fmt.Errorf("ошибка сжатия ответа: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("ошибка сжатия ответа: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func decompressPayload(compressed []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка распаковки ответа: %v", err)
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
+
+// PayloadArchive — архив ответов API в raw_payloads и raw_season_payloads
+// (см. api.EnableArchive и api.NewArchiveProvider).
+type PayloadArchive struct{}
+
+func (PayloadArchive) SaveFixturePayload(ctx context.Context, endpoint string, fixtureID int, body []byte) error {
+	return SaveRawPayload(ctx, endpoint, fixtureID, body)
+}
+
+func (PayloadArchive) FixturePayload(ctx context.Context, endpoint string, fixtureID int) ([]byte, error) {
+	body, err := GetRawPayload(ctx, endpoint, fixtureID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return body, err
+}
+
+func (PayloadArchive) SaveSeasonPayload(ctx context.Context, endpoint string, leagueID int, season string, body []byte) error {
+	return SaveSeasonPayload(ctx, endpoint, leagueID, season, body)
+}
+
+func (PayloadArchive) SeasonPayload(ctx context.Context, endpoint string, leagueID int, season string) ([]byte, error) {
+	body, err := GetSeasonPayload(ctx, endpoint, leagueID, season)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return body, err
+}
+
+// GetArchivedMatches возвращает сохраненные матчи, для которых есть архив статистики.
+// leagueID = 0 и пустой season отключают соответствующий фильтр.
+func GetArchivedMatches(ctx context.Context, leagueID int, season string) ([]models.Match, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	query := `
+        SELECT m.id, m.league_id, m.date, m.home_team_id, m.away_team_id, m.home_score, m.away_score
+        FROM matches m
+        WHERE EXISTS (SELECT 1 FROM raw_payloads r WHERE r.fixture_id = m.id AND r.endpoint = 'fixtures/statistics')
+          AND ($1 = 0 OR m.league_id = $1)
+          AND ($2 = '' OR m.season = $2)
+        ORDER BY m.date ASC
+    `
+	rows, err := DB.QueryContext(ctx, query, leagueID, season)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения архивных матчей: %v", err)
+	}
+	defer rows.Close()
+
+	var matches []models.Match
+	for rows.Next() {
+		var match models.Match
+		err := rows.Scan(&match.ID, &match.LeagueID, &match.Date, &match.HomeTeamID, &match.AwayTeamID, &match.HomeScore, &match.AwayScore)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка сканирования матча: %v", err)
+		}
+		matches = append(matches, match)
+	}
+
+	return matches, nil
+}
@@ -1,13 +1,20 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"football-data-miner/internal/models"
 )
 
-func SaveTeamIfNotExists(teamID int, teamName string) error {
-	_, err := DB.Exec(`
+// ErrMatchExists — матч уже сохранен ранее; SaveMatchDetails в этом случае ничего не пишет.
+var ErrMatchExists = errors.New("матч уже существует")
+
+func SaveTeamIfNotExists(ctx context.Context, teamID int, teamName string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	_, err := DB.ExecContext(ctx, `
         INSERT INTO teams (id, fullname)
         VALUES ($1, $2)
         ON CONFLICT (id) DO NOTHING
@@ -15,39 +22,60 @@ func SaveTeamIfNotExists(teamID int, teamName string) error {
 	return err
 }
 
-func SaveCoachIfNotExists(coachID int, coachName string) error {
-	_, err := DB.Exec(`
-        INSERT INTO coaches (id, fullname)
-        VALUES ($1, $2)
-        ON CONFLICT (id) DO NOTHING
-    `, coachID, coachName)
+// SaveTeam сохраняет или обновляет расширенные данные команды из /teams.
+func SaveTeam(ctx context.Context, team models.Team) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	_, err := DB.ExecContext(ctx, `
+        INSERT INTO teams (id, fullname, code, country, founded, national, venue_id)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        ON CONFLICT (id) DO UPDATE SET
+            fullname = EXCLUDED.fullname, code = EXCLUDED.code, country = EXCLUDED.country,
+            founded = EXCLUDED.founded, national = EXCLUDED.national, venue_id = EXCLUDED.venue_id
+    `, team.ID, team.Fullname, team.Code, team.Country, team.Founded, team.National, team.VenueID)
 	return err
 }
 
-func SavePlayerIfNotExists(playerID int, playerName string) error {
-	_, err := DB.Exec(`
-        INSERT INTO players (id, fullname)
-        VALUES ($1, $2)
-        ON CONFLICT (id) DO NOTHING
-    `, playerID, playerName)
+// SaveVenue сохраняет или обновляет стадион из /teams.
+func SaveVenue(ctx context.Context, venue models.Venue) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	_, err := DB.ExecContext(ctx, `
+        INSERT INTO venues (id, name, address, city, capacity, surface)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (id) DO UPDATE SET
+            name = EXCLUDED.name, address = EXCLUDED.address, city = EXCLUDED.city,
+            capacity = EXCLUDED.capacity, surface = EXCLUDED.surface
+    `, venue.ID, venue.Name, venue.Address, venue.City, venue.Capacity, venue.Surface)
 	return err
 }
-func SaveMatchDetails(match models.Match, leagueID int, season string, stats models.MatchStatistics, lineups []models.Lineup) error {
-	tx, err := DB.Begin()
+
+// SaveMatchDetails сохраняет матч целиком в одной транзакции: команды, стадион, тренеров
+// и игроков составов вместе со статистикой, событиями и отсутствиями, и отмечает его
+// в ingestion_progress как saved. При любой ошибке или отмене ctx в БД не остается
+// ничего из этого матча.
+func SaveMatchDetails(ctx context.Context, match models.Match, leagueID int, season string, stats models.MatchStatistics, lineups []models.Lineup, events []models.MatchEvent, absences []models.PlayerAbsence) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	tx, err := DB.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("ошибка начала транзакции: %v", err)
 	}
 	defer tx.Rollback()
 
+	if err := saveMatchParticipants(ctx, tx, match, lineups); err != nil {
+		return fmt.Errorf("ошибка сохранения участников матча ID=%d: %v", match.ID, err)
+	}
+
 	// Сохраняем матч
-	if err := saveMatch(tx, match, leagueID, season); err != nil {
-		return fmt.Errorf("ошибка сохранения матча ID=%d: %v", match.ID, err)
+	if err := saveMatch(ctx, tx, match, leagueID, season); err != nil {
+		return fmt.Errorf("ошибка сохранения матча ID=%d: %w", match.ID, err)
 	}
 
 	// Сохраняем статистику матча
 	if !stats.IsDefault() {
 		stats.MatchID = match.ID
-		if err := saveMatchStatistics(tx, stats); err != nil {
+		if err := saveMatchStatistics(ctx, tx, stats); err != nil {
 			return fmt.Errorf("ошибка сохранения статистики матча ID=%d: %v", match.ID, err)
 		}
 
@@ -57,7 +85,7 @@ func SaveMatchDetails(match models.Match, leagueID int, season string, stats mod
 				continue // Пропускаем пустые составы
 			}
 			lineup.MatchID = match.ID
-			if err := saveLineup(tx, lineup); err != nil {
+			if err := saveLineup(ctx, tx, lineup); err != nil {
 				return fmt.Errorf("ошибка сохранения состава игрока ID=%d для матча ID=%d: %v", lineup.PlayerID, match.ID, err)
 			}
 		}
@@ -65,6 +93,26 @@ func SaveMatchDetails(match models.Match, leagueID int, season string, stats mod
 		fmt.Printf("Матч ID=%d: статистика и составы отсутствуют. Пропускаем.\n", match.ID)
 	}
 
+	// Сохраняем события матча
+	for _, event := range events {
+		event.MatchID = match.ID
+		if err := saveMatchEvent(ctx, tx, event); err != nil {
+			return fmt.Errorf("ошибка сохранения события #%d для матча ID=%d: %v", event.Sequence, match.ID, err)
+		}
+	}
+
+	// Сохраняем отсутствующих игроков
+	for _, absence := range absences {
+		absence.MatchID = match.ID
+		if err := savePlayerAbsence(ctx, tx, absence); err != nil {
+			return fmt.Errorf("ошибка сохранения отсутствия игрока ID=%d для матча ID=%d: %v", absence.PlayerID, match.ID, err)
+		}
+	}
+
+	if err := markMatchSaved(ctx, tx, leagueID, season, match.ID); err != nil {
+		return fmt.Errorf("ошибка сохранения матча ID=%d: %v", match.ID, err)
+	}
+
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("ошибка коммита транзакции: %v", err)
 	}
@@ -73,16 +121,140 @@ func SaveMatchDetails(match models.Match, leagueID int, season string, stats mod
 	return nil
 }
 
-func saveMatch(tx *sql.Tx, match models.Match, leagueID int, season string) error {
+// ReplaceMatchDetails перезаписывает статистику и составы уже сохраненного матча.
+// Используется при повторном разборе архивных ответов API.
+func ReplaceMatchDetails(ctx context.Context, match models.Match, stats models.MatchStatistics, lineups []models.Lineup) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := saveMatchParticipants(ctx, tx, match, lineups); err != nil {
+		return fmt.Errorf("ошибка сохранения участников матча ID=%d: %v", match.ID, err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM lineups WHERE match_id = $1`, match.ID); err != nil {
+		return fmt.Errorf("ошибка удаления составов матча ID=%d: %v", match.ID, err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM match_statistics WHERE match_id = $1`, match.ID); err != nil {
+		return fmt.Errorf("ошибка удаления статистики матча ID=%d: %v", match.ID, err)
+	}
+	_, err = tx.ExecContext(ctx, `
+        UPDATE matches
+        SET home_coach_id = $2, away_coach_id = $3, home_formation = $4, away_formation = $5
+        WHERE id = $1
+    `, match.ID, match.HomeCoachID, match.AwayCoachID, match.HomeFormation, match.AwayFormation)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления матча ID=%d: %v", match.ID, err)
+	}
+
+	if !stats.IsDefault() {
+		stats.MatchID = match.ID
+		if err := saveMatchStatistics(ctx, tx, stats); err != nil {
+			return fmt.Errorf("ошибка сохранения статистики матча ID=%d: %v", match.ID, err)
+		}
+		for _, lineup := range lineups {
+			if lineup.IsEmpty() {
+				continue
+			}
+			lineup.MatchID = match.ID
+			if err := saveLineup(ctx, tx, lineup); err != nil {
+				return fmt.Errorf("ошибка сохранения состава игрока ID=%d для матча ID=%d: %v", lineup.PlayerID, match.ID, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка коммита транзакции: %v", err)
+	}
+	return nil
+}
+
+// saveMatchParticipants добавляет недостающие команды, стадион, тренеров и игроков матча.
+func saveMatchParticipants(ctx context.Context, tx *sql.Tx, match models.Match, lineups []models.Lineup) error {
+	for _, team := range []struct {
+		id   int
+		name string
+	}{{match.HomeTeamID, match.HomeTeamName}, {match.AwayTeamID, match.AwayTeamName}} {
+		if err := saveTeamIfNotExists(ctx, tx, team.id, team.name); err != nil {
+			return err
+		}
+	}
+
+	if match.VenueID != nil {
+		_, err := tx.ExecContext(ctx, `
+            INSERT INTO venues (id, name, city)
+            VALUES ($1, $2, $3)
+            ON CONFLICT (id) DO NOTHING
+        `, *match.VenueID, match.VenueName, match.VenueCity)
+		if err != nil {
+			return fmt.Errorf("ошибка сохранения стадиона %d: %v", *match.VenueID, err)
+		}
+	}
+
+	for _, coach := range []models.Coach{{ID: match.HomeCoachID, Fullname: match.HomeCoachName}, {ID: match.AwayCoachID, Fullname: match.AwayCoachName}} {
+		if coach.ID == 0 {
+			continue
+		}
+		_, err := tx.ExecContext(ctx, `
+            INSERT INTO coaches (id, fullname)
+            VALUES ($1, $2)
+            ON CONFLICT (id) DO NOTHING
+        `, coach.ID, coach.Fullname)
+		if err != nil {
+			return fmt.Errorf("ошибка сохранения тренера %d: %v", coach.ID, err)
+		}
+	}
+
+	for _, lineup := range lineups {
+		if lineup.IsEmpty() {
+			continue
+		}
+		if err := savePlayerIfNotExists(ctx, tx, lineup.PlayerID, lineup.PlayerName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func saveTeamIfNotExists(ctx context.Context, tx *sql.Tx, teamID int, teamName string) error {
+	_, err := tx.ExecContext(ctx, `
+        INSERT INTO teams (id, fullname)
+        VALUES ($1, $2)
+        ON CONFLICT (id) DO NOTHING
+    `, teamID, teamName)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения команды %d: %v", teamID, err)
+	}
+	return nil
+}
+
+func savePlayerIfNotExists(ctx context.Context, tx *sql.Tx, playerID int, playerName string) error {
+	_, err := tx.ExecContext(ctx, `
+        INSERT INTO players (id, fullname)
+        VALUES ($1, $2)
+        ON CONFLICT (id) DO NOTHING
+    `, playerID, playerName)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения игрока %d: %v", playerID, err)
+	}
+	return nil
+}
+
+func saveMatch(ctx context.Context, tx *sql.Tx, match models.Match, leagueID int, season string) error {
 	query := `
         INSERT INTO matches (
             id, date, league_id, season, home_team_id, away_team_id,
             home_score, away_score, home_coach_id, away_coach_id,
-            home_formation, away_formation, round
-        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+            home_formation, away_formation, round, status,
+            home_halftime, away_halftime, home_extratime, away_extratime,
+            home_penalty, away_penalty, venue_id, referee
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)
         ON CONFLICT (id) DO NOTHING
     `
-	result, err := tx.Exec(query,
+	result, err := tx.ExecContext(ctx, query,
 		match.ID,
 		match.Date,
 		leagueID,
@@ -96,6 +268,15 @@ func saveMatch(tx *sql.Tx, match models.Match, leagueID int, season string) erro
 		match.HomeFormation,
 		match.AwayFormation,
 		match.Round,
+		match.Status,
+		match.HomeHalftime,
+		match.AwayHalftime,
+		match.HomeExtratime,
+		match.AwayExtratime,
+		match.HomePenalty,
+		match.AwayPenalty,
+		match.VenueID,
+		match.Referee,
 	)
 	if err != nil {
 		return fmt.Errorf("ошибка сохранения матча ID=%d: %v", match.ID, err)
@@ -103,12 +284,12 @@ func saveMatch(tx *sql.Tx, match models.Match, leagueID int, season string) erro
 
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		return fmt.Errorf("матч ID=%d уже существует", match.ID)
+		return fmt.Errorf("%w: ID=%d", ErrMatchExists, match.ID)
 	}
 
 	return nil
 }
-func saveMatchStatistics(tx *sql.Tx, stats models.MatchStatistics) error {
+func saveMatchStatistics(ctx context.Context, tx *sql.Tx, stats models.MatchStatistics) error {
 	if stats.IsDefault() {
 		fmt.Printf("Матч ID=%d: статистика отсутствует. Пропускаем.\n", stats.MatchID)
 		return nil
@@ -131,11 +312,13 @@ func saveMatchStatistics(tx *sql.Tx, stats models.MatchStatistics) error {
 	            home_goalkeeper_saves, away_goalkeeper_saves,
 	            home_total_passes, away_total_passes,
 	            home_passes_accurate, away_passes_accurate,
-	            home_passes_percentage, away_passes_percentage
-	        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33)
+	            home_passes_percentage, away_passes_percentage,
+	            home_expected_goals, away_expected_goals,
+	            home_goals_prevented, away_goals_prevented
+	        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37)
 	        ON CONFLICT (match_id) DO NOTHING
 	    `
-	_, err := tx.Exec(query,
+	_, err := tx.ExecContext(ctx, query,
 		stats.MatchID,
 		stats.HomeBallPossession, stats.AwayBallPossession,
 		stats.HomeShotsOnGoal, stats.AwayShotsOnGoal,
@@ -153,6 +336,8 @@ func saveMatchStatistics(tx *sql.Tx, stats models.MatchStatistics) error {
 		stats.HomeTotalPasses, stats.AwayTotalPasses,
 		stats.HomePassesAccurate, stats.AwayPassesAccurate,
 		stats.HomePassesPercentage, stats.AwayPassesPercentage,
+		stats.HomeExpectedGoals, stats.AwayExpectedGoals,
+		stats.HomeGoalsPrevented, stats.AwayGoalsPrevented,
 	)
 	if err != nil {
 		return fmt.Errorf("ошибка сохранения статистики: %v", err)
@@ -160,7 +345,7 @@ func saveMatchStatistics(tx *sql.Tx, stats models.MatchStatistics) error {
 	return nil
 }
 
-func saveLineup(tx *sql.Tx, lineup models.Lineup) error {
+func saveLineup(ctx context.Context, tx *sql.Tx, lineup models.Lineup) error {
 	query := `
         INSERT INTO lineups (
             match_id, team_id, player_id, pos, is_substitute,
@@ -169,14 +354,17 @@ func saveLineup(tx *sql.Tx, lineup models.Lineup) error {
             dribbles_success, duels_won, passes_total,
             passes_accuracy, tackles_total,tackles_blocks, tackles_interceptions, shots_total,
             shots_on, goals_conceded, goals_saved,
-            minutes, captain, rating
+            minutes, captain, rating, number, dribbles_past,
+            duels_total, key_passes, offsides, penalty_won,
+            penalty_committed, penalty_scored, penalty_missed, penalty_saved
         ) VALUES (
             $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13,
-            $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26
+            $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26,
+            $27, $28, $29, $30, $31, $32, $33, $34, $35, $36
         )
         ON CONFLICT (match_id, team_id, player_id) DO NOTHING
     `
-	_, err := tx.Exec(query,
+	_, err := tx.ExecContext(ctx, query,
 		lineup.MatchID,
 		lineup.TeamID,
 		lineup.PlayerID,
@@ -203,6 +391,66 @@ func saveLineup(tx *sql.Tx, lineup models.Lineup) error {
 		lineup.Minutes,
 		lineup.Captain,
 		lineup.Rating,
+		lineup.Number,
+		lineup.DribblesPast,
+		lineup.DuelsTotal,
+		lineup.KeyPasses,
+		lineup.Offsides,
+		lineup.PenaltyWon,
+		lineup.PenaltyCommitted,
+		lineup.PenaltyScored,
+		lineup.PenaltyMissed,
+		lineup.PenaltySaved,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка выполнения запроса: %v", err)
+	}
+	return nil
+}
+
+func saveMatchEvent(ctx context.Context, tx *sql.Tx, event models.MatchEvent) error {
+	query := `
+        INSERT INTO match_events (
+            match_id, sequence, team_id, player_id, assist_id,
+            elapsed, extra, type, detail, comments
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+        ON CONFLICT (match_id, sequence) DO NOTHING
+    `
+	_, err := tx.ExecContext(ctx, query,
+		event.MatchID,
+		event.Sequence,
+		event.TeamID,
+		event.PlayerID,
+		event.AssistID,
+		event.Elapsed,
+		event.Extra,
+		event.Type,
+		event.Detail,
+		event.Comments,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка выполнения запроса: %v", err)
+	}
+	return nil
+}
+
+func savePlayerAbsence(ctx context.Context, tx *sql.Tx, absence models.PlayerAbsence) error {
+	// Пропускающий матч игрок мог еще ни разу не попасть в составы
+	if err := savePlayerIfNotExists(ctx, tx, absence.PlayerID, absence.PlayerName); err != nil {
+		return err
+	}
+
+	query := `
+        INSERT INTO player_absences (match_id, player_id, team_id, type, reason)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (match_id, player_id) DO NOTHING
+    `
+	_, err := tx.ExecContext(ctx, query,
+		absence.MatchID,
+		absence.PlayerID,
+		absence.TeamID,
+		absence.Type,
+		absence.Reason,
 	)
 	if err != nil {
 		return fmt.Errorf("ошибка выполнения запроса: %v", err)
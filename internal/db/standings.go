@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"football-data-miner/internal/models"
+)
+
+// HasStandingsSnapshot проверяет, сохранена ли таблица после тура.
+func HasStandingsSnapshot(ctx context.Context, leagueID int, season, round string) (bool, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	var exists bool
+	err := DB.QueryRowContext(ctx, `
+        SELECT EXISTS (
+            SELECT 1
+            FROM standings_snapshots
+            WHERE league_id = $1 AND season = $2 AND round = $3
+        )
+    `, leagueID, season, round).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("ошибка проверки таблицы лиги %d, сезон %s, тур %s: %v", leagueID, season, round, err)
+	}
+	return exists, nil
+}
+
+// SaveStandingsSnapshot сохраняет снимок таблицы одной транзакцией.
+func SaveStandingsSnapshot(ctx context.Context, rows []models.StandingRow) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %v", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+        INSERT INTO standings_snapshots (
+            league_id, season, round, team_id, group_name, rank, points, goals_diff,
+            played, win, draw, lose, goals_for, goals_against,
+            home_played, away_played, form, description, api_updated_at, captured_at
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, NOW())
+        ON CONFLICT (league_id, season, round, team_id) DO UPDATE SET
+            group_name = EXCLUDED.group_name, rank = EXCLUDED.rank, points = EXCLUDED.points,
+            goals_diff = EXCLUDED.goals_diff, played = EXCLUDED.played, win = EXCLUDED.win,
+            draw = EXCLUDED.draw, lose = EXCLUDED.lose, goals_for = EXCLUDED.goals_for,
+            goals_against = EXCLUDED.goals_against, home_played = EXCLUDED.home_played,
+            away_played = EXCLUDED.away_played, form = EXCLUDED.form,
+            description = EXCLUDED.description, api_updated_at = EXCLUDED.api_updated_at,
+            captured_at = EXCLUDED.captured_at
+    `
+	for _, row := range rows {
+		if err := saveTeamIfNotExists(ctx, tx, row.TeamID, row.TeamName); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, query,
+			row.LeagueID, row.Season, row.Round, row.TeamID, row.Group, row.Rank, row.Points, row.GoalsDiff,
+			row.Played, row.Win, row.Draw, row.Lose, row.GoalsFor, row.GoalsAgainst,
+			row.HomePlayed, row.AwayPlayed, row.Form, row.Description, row.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("ошибка сохранения строки таблицы для команды %d: %v", row.TeamID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка коммита транзакции: %v", err)
+	}
+	return nil
+}
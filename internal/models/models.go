@@ -1,13 +1,38 @@
 package models
 
+import "time"
+
 type Team struct {
 	ID       int    `json:"id"`
 	Fullname string `json:"fullname"`
+	Code     string `json:"code"`
+	Country  string `json:"country"`
+	Founded  *int   `json:"founded"`
+	National bool   `json:"national"`
+	VenueID  *int   `json:"venue_id"`
 }
 
-type Player struct {
+type Venue struct {
 	ID       int    `json:"id"`
-	Fullname string `json:"fullname"`
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	City     string `json:"city"`
+	Capacity int    `json:"capacity"`
+	Surface  string `json:"surface"`
+}
+
+type Player struct {
+	ID           int     `json:"id"`
+	Fullname     string  `json:"fullname"`
+	Firstname    string  `json:"firstname"`
+	Lastname     string  `json:"lastname"`
+	BirthDate    *string `json:"birth_date"` // YYYY-MM-DD
+	BirthPlace   string  `json:"birth_place"`
+	BirthCountry string  `json:"birth_country"`
+	Nationality  string  `json:"nationality"`
+	Height       *int    `json:"height"` // см
+	Weight       *int    `json:"weight"` // кг
+	Photo        string  `json:"photo"`
 }
 
 type Coach struct {
@@ -16,6 +41,7 @@ type Coach struct {
 }
 type Match struct {
 	ID            int    `json:"id"`
+	LeagueID      int    `json:"league_id"`
 	Date          string `json:"date"`
 	HomeTeamID    int    `json:"home_team_id"`
 	AwayTeamID    int    `json:"away_team_id"`
@@ -25,13 +51,61 @@ type Match struct {
 	AwayScore     *int   `json:"away_score"`
 	HomeCoachID   int    `json:"home_coach_id"`
 	AwayCoachID   int    `json:"away_coach_id"`
+	HomeCoachName string `json:"home_coach_name"`
+	AwayCoachName string `json:"away_coach_name"`
 	HomeFormation string `json:"home_formation"`
 	AwayFormation string `json:"away_formation"`
 	Round         string `json:"round"`
-	//HomeExtratime *int   // Используем указатели для nullable значений
-	//AwayExtratime *int
-	//HomePenalty   *int
-	//AwayPenalty   *int
+	Status        string `json:"status"`        // Короткий статус api-sports: NS, PST, FT, AET, PEN...
+	HomeHalftime  *int   `json:"home_halftime"` // Используем указатели для nullable значений
+	AwayHalftime  *int   `json:"away_halftime"`
+	HomeExtratime *int   `json:"home_extratime"`
+	AwayExtratime *int   `json:"away_extratime"`
+	HomePenalty   *int   `json:"home_penalty"`
+	AwayPenalty   *int   `json:"away_penalty"`
+	VenueID       *int   `json:"venue_id"`
+	VenueName     string `json:"venue_name"`
+	VenueCity     string `json:"venue_city"`
+	Referee       string `json:"referee"`
+}
+
+// Короткие статусы матча api-sports (fixture.status.short).
+const (
+	StatusFinished         = "FT"
+	StatusAfterExtraTime   = "AET"
+	StatusPenaltyShootout  = "PEN"
+	StatusNotStarted       = "NS"
+	StatusPostponed        = "PST"
+	StatusCancelled        = "CANC"
+	StatusAbandoned        = "ABD"
+	StatusTechnicalLoss    = "AWD"
+	StatusWalkOver         = "WO"
+	StatusTimeToBeDefined  = "TBD"
+	StatusMatchSuspended   = "SUSP"
+	StatusMatchInterrupted = "INT"
+)
+
+// IsFinished — матч сыгран и счет окончательный.
+func (m *Match) IsFinished() bool {
+	switch m.Status {
+	case StatusFinished, StatusAfterExtraTime, StatusPenaltyShootout:
+		return true
+	}
+	return false
+}
+
+// IsCancelled — матч не будет сыгран (отменен, прерван без переигровки, присуждено поражение).
+func (m *Match) IsCancelled() bool {
+	switch m.Status {
+	case StatusCancelled, StatusAbandoned, StatusTechnicalLoss, StatusWalkOver:
+		return true
+	}
+	return false
+}
+
+// IsPending — матч еще может быть сыгран: не начат, перенесен, идет или прерван.
+func (m *Match) IsPending() bool {
+	return !m.IsFinished() && !m.IsCancelled()
 }
 
 type MatchStatistics struct {
@@ -68,12 +142,19 @@ type MatchStatistics struct {
 	AwayPassesAccurate   int `json:"away_passes_accurate"`
 	HomePassesPercentage int `json:"home_passes_percentage"`
 	AwayPassesPercentage int `json:"away_passes_percentage"`
+	// xG и предотвращенные голы есть не во всех лигах, nil — нет данных
+	HomeExpectedGoals  *float64 `json:"home_expected_goals"`
+	AwayExpectedGoals  *float64 `json:"away_expected_goals"`
+	HomeGoalsPrevented *float64 `json:"home_goals_prevented"`
+	AwayGoalsPrevented *float64 `json:"away_goals_prevented"`
 }
 
 type Lineup struct {
 	MatchID              int     `json:"match_id"`
 	TeamID               int     `json:"team_id"`
 	PlayerID             int     `json:"player_id"`
+	PlayerName           string  `json:"player_name"`
+	Number               int     `json:"number"`
 	Position             string  `json:"pos"`
 	IsSubstitute         bool    `json:"is_substitute"`
 	YellowCards          int     `json:"yellow_cards"`
@@ -84,8 +165,11 @@ type Lineup struct {
 	FoulsDrawn           int     `json:"fouls_drawn"`
 	DribblesAttempts     int     `json:"dribbles_attempts"`
 	DribblesSuccess      int     `json:"dribbles_success"`
+	DribblesPast         int     `json:"dribbles_past"`
 	DuelsWon             int     `json:"duels_won"`
+	DuelsTotal           int     `json:"duels_total"`
 	PassesTotal          int     `json:"passes_total"`
+	KeyPasses            int     `json:"key_passes"`
 	PassesAccuracy       int     `json:"passes_accuracy"`
 	TacklesTotal         int     `json:"tackles_total"`
 	TacklesBlocks        int     `json:"tackles_blocks"`
@@ -97,15 +181,99 @@ type Lineup struct {
 	Minutes              int     `json:"minutes"`
 	Captain              bool    `json:"captain"`
 	Rating               float64 `json:"rating"`
+	Offsides             int     `json:"offsides"`
+	PenaltyWon           int     `json:"penalty_won"`
+	PenaltyCommitted     int     `json:"penalty_committed"`
+	PenaltyScored        int     `json:"penalty_scored"`
+	PenaltyMissed        int     `json:"penalty_missed"`
+	PenaltySaved         int     `json:"penalty_saved"`
+}
+
+// MatchEvent — событие матча из /fixtures/events: гол, карточка, замена или решение VAR.
+// Для замен PlayerID — ушедший игрок, AssistID — вышедший.
+type MatchEvent struct {
+	MatchID  int    `json:"match_id"`
+	Sequence int    `json:"sequence"` // Порядковый номер события в ответе API
+	TeamID   int    `json:"team_id"`
+	PlayerID *int   `json:"player_id"`
+	AssistID *int   `json:"assist_id"`
+	Elapsed  int    `json:"elapsed"`
+	Extra    *int   `json:"extra"`
+	Type     string `json:"type"`
+	Detail   string `json:"detail"`
+	Comments string `json:"comments"`
+}
+
+// PlayerAbsence — игрок, пропускающий матч (/injuries): Type — "Missing Fixture" или "Questionable",
+// Reason — причина (травма, дисквалификация...).
+type PlayerAbsence struct {
+	MatchID    int    `json:"match_id"`
+	PlayerID   int    `json:"player_id"`
+	PlayerName string `json:"player_name"`
+	TeamID     int    `json:"team_id"`
+	Type       string `json:"type"`
+	Reason     string `json:"reason"`
+}
+
+// Рынки коэффициентов, которые сохраняются из /odds.
+const (
+	Market1X2       = "1x2"
+	MarketOverUnder = "over_under"
+	MarketBTTS      = "btts"
+)
+
+// Odd — коэффициент букмекера на исход: Selection — "Home"/"Draw"/"Away", "Over 2.5", "Yes"...
+type Odd struct {
+	FixtureID    int     `json:"fixture_id"`
+	BookmakerID  int     `json:"bookmaker_id"`
+	Bookmaker    string  `json:"bookmaker"`
+	Market       string  `json:"market"`
+	Selection    string  `json:"selection"`
+	Value        float64 `json:"value"`
+	APIUpdatedAt string  `json:"api_updated_at"`
+}
+
+// StandingRow — строка официальной турнирной таблицы (/standings) на момент после тура Round.
+type StandingRow struct {
+	LeagueID     int    `json:"league_id"`
+	Season       string `json:"season"`
+	Round        string `json:"round"`
+	TeamID       int    `json:"team_id"`
+	TeamName     string `json:"team_name"`
+	Group        string `json:"group"`
+	Rank         int    `json:"rank"`
+	Points       int    `json:"points"`
+	GoalsDiff    int    `json:"goals_diff"`
+	Played       int    `json:"played"`
+	Win          int    `json:"win"`
+	Draw         int    `json:"draw"`
+	Lose         int    `json:"lose"`
+	GoalsFor     int    `json:"goals_for"`
+	GoalsAgainst int    `json:"goals_against"`
+	HomePlayed   int    `json:"home_played"`
+	AwayPlayed   int    `json:"away_played"`
+	Form         string `json:"form"`
+	Description  string `json:"description"`
+	UpdatedAt    string `json:"updated_at"`
 }
 
 type MatchesOfSeason struct {
 	Response []struct {
 		Fixture struct {
-			ID   int    `json:"id"`
-			Date string `json:"date"`
+			ID     int    `json:"id"`
+			Date   string `json:"date"`
+			Status struct {
+				Short string `json:"short"`
+			} `json:"status"`
+			Referee *string `json:"referee"`
+			Venue   struct {
+				ID   *int   `json:"id"`
+				Name string `json:"name"`
+				City string `json:"city"`
+			} `json:"venue"`
 		} `json:"fixture"`
 		League struct {
+			ID    int    `json:"id"`
 			Round string `json:"round"`
 		} `json:"league"`
 		Teams struct {
@@ -119,18 +287,22 @@ type MatchesOfSeason struct {
 			} `json:"away"`
 		} `json:"teams"`
 		Score struct {
+			Halftime struct {
+				Home *int `json:"home"` // Nullable значение
+				Away *int `json:"away"`
+			} `json:"halftime"`
 			Fulltime struct {
-				Home int `json:"home"` // Nullable значение
-				Away int `json:"away"`
+				Home *int `json:"home"`
+				Away *int `json:"away"`
 			} `json:"fulltime"`
-			/*Extratime struct {
-				Home *int `json:"home"` // Nullable значение
+			Extratime struct {
+				Home *int `json:"home"`
 				Away *int `json:"away"`
 			} `json:"extratime"`
 			Penalty struct {
-				Home *int `json:"home"` // Nullable значение
+				Home *int `json:"home"`
 				Away *int `json:"away"`
-			} `json:"penalty"`*/
+			} `json:"penalty"`
 		} `json:"score"`
 	} `json:"response"`
 }
@@ -139,6 +311,64 @@ type Season struct {
 	Season   string // Год сезона (например, "2023")
 }
 
+// IngestionProgress — состояние загрузки матча из ingestion_progress.
+type IngestionProgress struct {
+	MatchID       int        `json:"match_id"`
+	LeagueID      int        `json:"league_id"`
+	Season        string     `json:"season"`
+	State         string     `json:"state"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error"`
+	NextAttemptAt *time.Time `json:"next_attempt_at"` // Для failed: раньше этого времени не повторять
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// Состояния загрузки матча. Saved, skipped и abandoned — конечные: матч больше не запрашивается.
+// Parked — матч исчерпал попытки и ждет решения оператора (повторить или отказаться).
+const (
+	ProgressPending   = "pending"
+	ProgressFetched   = "fetched"
+	ProgressSaved     = "saved"
+	ProgressFailed    = "failed"
+	ProgressSkipped   = "skipped"
+	ProgressParked    = "parked"
+	ProgressAbandoned = "abandoned"
+)
+
+// IsDone — матч загружен, пропущен намеренно или от него отказались.
+func (p IngestionProgress) IsDone() bool {
+	return p.State == ProgressSaved || p.State == ProgressSkipped || p.State == ProgressAbandoned
+}
+
+// IsWaiting — последняя попытка не удалась, и время следующей еще не пришло.
+func (p IngestionProgress) IsWaiting(now time.Time) bool {
+	return p.State == ProgressFailed && p.NextAttemptAt != nil && now.Before(*p.NextAttemptAt)
+}
+
+// LeagueSeason — запись очереди league_seasons. Сезоны с большим Priority
+// берутся в обработку раньше. Coverage — какие данные api-sports отдает по сезону.
+type LeagueSeason struct {
+	LeagueID    int      `json:"league_id"`
+	Season      string   `json:"season"`
+	LeagueName  string   `json:"league_name"`
+	Country     string   `json:"country"`
+	StartDate   *string  `json:"start_date"` // YYYY-MM-DD
+	EndDate     *string  `json:"end_date"`
+	Priority    int      `json:"priority"`
+	IsProcessed bool     `json:"is_processed"`
+	Coverage    Coverage `json:"coverage"`
+}
+
+type Coverage struct {
+	Events     bool `json:"events"`
+	Lineups    bool `json:"lineups"`
+	Statistics bool `json:"statistics"`
+	Players    bool `json:"players"`
+	Standings  bool `json:"standings"`
+	Injuries   bool `json:"injuries"`
+	Odds       bool `json:"odds"`
+}
+
 func (s *MatchStatistics) IsDefault() bool {
 	defaultStats := MatchStatistics{}
 	return s.HomeBallPossession == defaultStats.HomeBallPossession &&
@@ -172,7 +402,11 @@ func (s *MatchStatistics) IsDefault() bool {
 		s.HomePassesAccurate == defaultStats.HomePassesAccurate &&
 		s.AwayPassesAccurate == defaultStats.AwayPassesAccurate &&
 		s.HomePassesPercentage == defaultStats.HomePassesPercentage &&
-		s.AwayPassesPercentage == defaultStats.AwayPassesPercentage
+		s.AwayPassesPercentage == defaultStats.AwayPassesPercentage &&
+		s.HomeExpectedGoals == nil &&
+		s.AwayExpectedGoals == nil &&
+		s.HomeGoalsPrevented == nil &&
+		s.AwayGoalsPrevented == nil
 }
 
 func (l *Lineup) IsEmpty() bool {
@@ -201,5 +435,15 @@ func (l *Lineup) IsEmpty() bool {
 		l.Captain == defaultLineup.Captain &&
 		l.Rating == defaultLineup.Rating &&
 		l.TacklesBlocks == defaultLineup.TacklesBlocks &&
-		l.TacklesInterceptions == defaultLineup.TacklesInterceptions
+		l.TacklesInterceptions == defaultLineup.TacklesInterceptions &&
+		l.Number == defaultLineup.Number &&
+		l.DribblesPast == defaultLineup.DribblesPast &&
+		l.DuelsTotal == defaultLineup.DuelsTotal &&
+		l.KeyPasses == defaultLineup.KeyPasses &&
+		l.Offsides == defaultLineup.Offsides &&
+		l.PenaltyWon == defaultLineup.PenaltyWon &&
+		l.PenaltyCommitted == defaultLineup.PenaltyCommitted &&
+		l.PenaltyScored == defaultLineup.PenaltyScored &&
+		l.PenaltyMissed == defaultLineup.PenaltyMissed &&
+		l.PenaltySaved == defaultLineup.PenaltySaved
 }
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS match_events (
+    match_id   INTEGER     NOT NULL REFERENCES matches (id),
+    sequence   INTEGER     NOT NULL,
+    team_id    INTEGER     NOT NULL,
+    player_id  INTEGER,
+    assist_id  INTEGER,
+    elapsed    INTEGER     NOT NULL,
+    extra      INTEGER,
+    type       VARCHAR(32) NOT NULL,
+    detail     VARCHAR(64) NOT NULL,
+    comments   TEXT        NOT NULL DEFAULT '',
+    PRIMARY KEY (match_id, sequence)
+);
+
+CREATE INDEX IF NOT EXISTS match_events_type_idx ON match_events (type);
@@ -0,0 +1,8 @@
+ALTER TABLE matches
+    ADD COLUMN IF NOT EXISTS status         VARCHAR(8),
+    ADD COLUMN IF NOT EXISTS home_halftime  INTEGER,
+    ADD COLUMN IF NOT EXISTS away_halftime  INTEGER,
+    ADD COLUMN IF NOT EXISTS home_extratime INTEGER,
+    ADD COLUMN IF NOT EXISTS away_extratime INTEGER,
+    ADD COLUMN IF NOT EXISTS home_penalty   INTEGER,
+    ADD COLUMN IF NOT EXISTS away_penalty   INTEGER;
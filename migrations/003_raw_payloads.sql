@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS raw_payloads (
+    endpoint   VARCHAR(64) NOT NULL,
+    fixture_id INTEGER     NOT NULL,
+    fetched_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    body       BYTEA       NOT NULL, -- тело ответа, сжатое gzip
+    PRIMARY KEY (endpoint, fixture_id)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE match_statistics
+    ADD COLUMN IF NOT EXISTS home_expected_goals  NUMERIC(5, 2),
+    ADD COLUMN IF NOT EXISTS away_expected_goals  NUMERIC(5, 2),
+    ADD COLUMN IF NOT EXISTS home_goals_prevented NUMERIC(5, 2),
+    ADD COLUMN IF NOT EXISTS away_goals_prevented NUMERIC(5, 2);
@@ -0,0 +1,11 @@
+ALTER TABLE lineups
+    ADD COLUMN IF NOT EXISTS number            INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS dribbles_past     INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS duels_total       INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS key_passes        INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS offsides          INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS penalty_won       INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS penalty_committed INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS penalty_scored    INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS penalty_missed    INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS penalty_saved     INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS standings_snapshots (
+    league_id      INTEGER      NOT NULL,
+    season         VARCHAR(9)   NOT NULL,
+    round          VARCHAR(64)  NOT NULL,
+    team_id        INTEGER      NOT NULL REFERENCES teams (id),
+    group_name     VARCHAR(128) NOT NULL DEFAULT '',
+    rank           INTEGER      NOT NULL,
+    points         INTEGER      NOT NULL,
+    goals_diff     INTEGER      NOT NULL,
+    played         INTEGER      NOT NULL,
+    win            INTEGER      NOT NULL,
+    draw           INTEGER      NOT NULL,
+    lose           INTEGER      NOT NULL,
+    goals_for      INTEGER      NOT NULL,
+    goals_against  INTEGER      NOT NULL,
+    home_played    INTEGER      NOT NULL,
+    away_played    INTEGER      NOT NULL,
+    form           VARCHAR(16)  NOT NULL DEFAULT '',
+    description    TEXT         NOT NULL DEFAULT '',
+    api_updated_at VARCHAR(32)  NOT NULL DEFAULT '',
+    captured_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (league_id, season, round, team_id)
+);
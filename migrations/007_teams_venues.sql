@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS venues (
+    id       INTEGER PRIMARY KEY,
+    name     VARCHAR(255) NOT NULL DEFAULT '',
+    address  VARCHAR(255) NOT NULL DEFAULT '',
+    city     VARCHAR(128) NOT NULL DEFAULT '',
+    capacity INTEGER      NOT NULL DEFAULT 0,
+    surface  VARCHAR(64)  NOT NULL DEFAULT ''
+);
+
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS code     VARCHAR(8)  NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS country  VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS founded  INTEGER,
+    ADD COLUMN IF NOT EXISTS national BOOLEAN     NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS venue_id INTEGER REFERENCES venues (id);
+
+ALTER TABLE matches
+    ADD COLUMN IF NOT EXISTS venue_id INTEGER REFERENCES venues (id),
+    ADD COLUMN IF NOT EXISTS referee  VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,11 @@
+ALTER TABLE players
+    ADD COLUMN IF NOT EXISTS firstname          VARCHAR(128) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS lastname           VARCHAR(128) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS birth_date         DATE,
+    ADD COLUMN IF NOT EXISTS birth_place        VARCHAR(128) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS birth_country      VARCHAR(64)  NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS nationality        VARCHAR(64)  NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS height             INTEGER,
+    ADD COLUMN IF NOT EXISTS weight             INTEGER,
+    ADD COLUMN IF NOT EXISTS photo              VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS profile_updated_at TIMESTAMPTZ;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS player_absences (
+    match_id  INTEGER     NOT NULL REFERENCES matches (id),
+    player_id INTEGER     NOT NULL REFERENCES players (id),
+    team_id   INTEGER     NOT NULL REFERENCES teams (id),
+    type      VARCHAR(32) NOT NULL,
+    reason    TEXT        NOT NULL DEFAULT '',
+    PRIMARY KEY (match_id, player_id)
+);
+
+CREATE INDEX IF NOT EXISTS player_absences_player_idx ON player_absences (player_id);
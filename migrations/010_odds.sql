@@ -0,0 +1,12 @@
+-- fixture_id без внешнего ключа: коэффициенты снимаются до того, как матч попадает в matches
+CREATE TABLE IF NOT EXISTS odds (
+    fixture_id     INTEGER       NOT NULL,
+    bookmaker_id   INTEGER       NOT NULL,
+    bookmaker      VARCHAR(64)   NOT NULL,
+    market         VARCHAR(16)   NOT NULL,
+    selection      VARCHAR(32)   NOT NULL,
+    value          NUMERIC(8, 3) NOT NULL,
+    api_updated_at VARCHAR(32)   NOT NULL DEFAULT '',
+    captured_at    TIMESTAMPTZ   NOT NULL,
+    PRIMARY KEY (fixture_id, bookmaker_id, market, selection, captured_at)
+);
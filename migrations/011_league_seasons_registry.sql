@@ -0,0 +1,16 @@
+ALTER TABLE league_seasons
+    ADD COLUMN IF NOT EXISTS priority            INTEGER      NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS league_name         VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS country             VARCHAR(64)  NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS start_date          DATE,
+    ADD COLUMN IF NOT EXISTS end_date            DATE,
+    ADD COLUMN IF NOT EXISTS coverage_events     BOOLEAN      NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS coverage_lineups    BOOLEAN      NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS coverage_statistics BOOLEAN      NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS coverage_players    BOOLEAN      NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS coverage_standings  BOOLEAN      NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS coverage_injuries   BOOLEAN      NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS coverage_odds       BOOLEAN      NOT NULL DEFAULT FALSE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS league_seasons_league_season_idx ON league_seasons (league_id, season);
+CREATE INDEX IF NOT EXISTS league_seasons_queue_idx ON league_seasons (is_processed, priority DESC, season);
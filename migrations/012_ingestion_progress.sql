@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS ingestion_progress (
+    match_id   INTEGER     PRIMARY KEY,
+    league_id  INTEGER     NOT NULL,
+    season     VARCHAR(16) NOT NULL,
+    state      VARCHAR(16) NOT NULL DEFAULT 'pending'
+        CHECK (state IN ('pending', 'fetched', 'saved', 'failed', 'skipped')),
+    attempts   INTEGER     NOT NULL DEFAULT 0,
+    last_error TEXT        NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS ingestion_progress_season_idx ON ingestion_progress (league_id, season, state);
+
+-- Уже сохраненные матчи считаются загруженными
+INSERT INTO ingestion_progress (match_id, league_id, season, state, attempts)
+SELECT id, league_id, season, 'saved', 1
+FROM matches
+ON CONFLICT (match_id) DO NOTHING;
@@ -0,0 +1,8 @@
+ALTER TABLE ingestion_progress
+    ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ;
+
+ALTER TABLE ingestion_progress DROP CONSTRAINT IF EXISTS ingestion_progress_state_check;
+ALTER TABLE ingestion_progress ADD CONSTRAINT ingestion_progress_state_check
+    CHECK (state IN ('pending', 'fetched', 'saved', 'failed', 'skipped', 'parked', 'abandoned'));
+
+CREATE INDEX IF NOT EXISTS ingestion_progress_parked_idx ON ingestion_progress (league_id, season) WHERE state = 'parked';
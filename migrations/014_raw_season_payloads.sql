@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS raw_season_payloads (
+    endpoint   VARCHAR(64) NOT NULL,
+    league_id  INTEGER     NOT NULL,
+    season     VARCHAR(9)  NOT NULL DEFAULT '', -- пусто для ответов по лиге целиком
+    fetched_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    body       BYTEA       NOT NULL, -- тело ответа, сжатое gzip
+    PRIMARY KEY (endpoint, league_id, season)
+);
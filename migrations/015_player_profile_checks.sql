@@ -0,0 +1,7 @@
+-- Когда игрок последний раз попадал в полный проход /players по его сезону, даже если
+-- API его не вернул: без этой отметки такой игрок держал бы сезон устаревшим навсегда.
+ALTER TABLE players
+    ADD COLUMN IF NOT EXISTS profile_checked_at TIMESTAMPTZ;
+
+UPDATE players SET profile_checked_at = profile_updated_at
+WHERE profile_checked_at IS NULL;